@@ -0,0 +1,30 @@
+module github.com/nandanurseptama/golang-crawler/cmd
+
+go 1.24.5
+
+require github.com/nandanurseptama/golang-crawler/crawler v0.0.0
+
+require github.com/nandanurseptama/golang-crawler/tiktok v0.0.0
+
+require github.com/nandanurseptama/golang-crawler/youtube v0.0.0
+
+require github.com/nandanurseptama/golang-crawler/server v0.0.0
+
+require (
+	github.com/chromedp/cdproto v0.0.0-20250724212937-08a3db8b4327 // indirect
+	github.com/chromedp/chromedp v0.14.1 // indirect
+	github.com/chromedp/sysutil v1.1.0 // indirect
+	github.com/go-json-experiment/json v0.0.0-20250725192818-e39067aee2d2 // indirect
+	github.com/gobwas/httphead v0.1.0 // indirect
+	github.com/gobwas/pool v0.2.1 // indirect
+	github.com/gobwas/ws v1.4.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+)
+
+replace github.com/nandanurseptama/golang-crawler/crawler => ../crawler
+
+replace github.com/nandanurseptama/golang-crawler/tiktok => ../tiktok
+
+replace github.com/nandanurseptama/golang-crawler/youtube => ../youtube
+
+replace github.com/nandanurseptama/golang-crawler/server => ../server
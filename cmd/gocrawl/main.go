@@ -0,0 +1,153 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+// Command gocrawl runs tiktok and youtube crawlers from command line.
+//
+// Usage:
+//
+//	gocrawl <platform> <command> [flags] <term>
+//
+// Run "gocrawl <platform>" to list commands of a platform,
+// and "gocrawl <platform> <command> -h" to list its flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/nandanurseptama/golang-crawler/server"
+)
+
+// flags registers method specific flags into param
+type flags func(fs *flag.FlagSet, param *server.Param)
+
+// methodFlags are flags of platform methods, see server.Methods
+var methodFlags = map[string]map[string]flags{
+	"tiktok":  tiktokFlags,
+	"youtube": youtubeFlags,
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "gocrawl err :", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 1 {
+		usage()
+		return flag.ErrHelp
+	}
+
+	methods, ok := server.Methods[args[0]]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown platform %s", args[0])
+	}
+
+	if len(args) < 2 {
+		platformUsage(args[0], methods)
+		return flag.ErrHelp
+	}
+
+	method, ok := methods[args[1]]
+	if !ok {
+		platformUsage(args[0], methods)
+		return fmt.Errorf("unknown %s command %s", args[0], args[1])
+	}
+
+	fs := flag.NewFlagSet(args[0]+" "+args[1], flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "%s\n\nUsage:\n  gocrawl %s %s [flags] <term>\n\nFlags:\n", method.Usage, args[0], args[1])
+		fs.PrintDefaults()
+	}
+
+	var opts options
+	opts.register(fs)
+	if register, ok := methodFlags[args[0]][args[1]]; ok {
+		register(fs, &opts.param)
+	}
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+
+	// term is either flag or the first argument
+	if opts.param.Term == "" {
+		opts.param.Term = strings.Join(fs.Args(), " ")
+	}
+	if opts.param.Term == "" && !method.OptionalTerm {
+		fs.Usage()
+		return errors.New("term is required")
+	}
+
+	if err := opts.init(); err != nil {
+		return err
+	}
+	defer opts.close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	out, err := opts.newWriter()
+	if err != nil {
+		return err
+	}
+
+	var crawlErr error
+	for item, err := range method.Crawl(ctx, opts.config, opts.param) {
+		if err != nil {
+			crawlErr = err
+			break
+		}
+		if err := out.Write(item); err != nil {
+			crawlErr = err
+			break
+		}
+	}
+
+	// items crawled before failure are kept
+	if err := out.Close(); err != nil && crawlErr == nil {
+		crawlErr = err
+	}
+
+	return crawlErr
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:\n  gocrawl <platform> <command> [flags] <term>\n\nPlatforms:")
+	for _, name := range sortedKeys(server.Methods) {
+		fmt.Fprintf(os.Stderr, "  %s\n", name)
+	}
+}
+
+func platformUsage(platform string, methods map[string]server.Method) {
+	fmt.Fprintf(os.Stderr, "Usage:\n  gocrawl %s <command> [flags] <term>\n\nCommands:\n", platform)
+	for _, name := range sortedKeys(methods) {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, methods[name].Usage)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
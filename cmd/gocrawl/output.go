@@ -0,0 +1,187 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// writer writes crawled items as soon as they are crawled
+type writer interface {
+	Write(item any) error
+	// Close flushes remaining output and closes underlying file
+	Close() error
+}
+
+func newWriter(format string, out io.WriteCloser) (writer, error) {
+	switch format {
+	case "json":
+		return &jsonWriter{out: out, buf: bufio.NewWriter(out)}, nil
+	case "jsonl":
+		return &jsonlWriter{out: out, enc: json.NewEncoder(out)}, nil
+	case "csv":
+		return &csvWriter{out: out, csv: csv.NewWriter(out)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format %s", format)
+}
+
+// jsonWriter writes items as a single json array
+type jsonWriter struct {
+	out   io.WriteCloser
+	buf   *bufio.Writer
+	total int
+}
+
+func (w *jsonWriter) Write(item any) error {
+	content, err := json.MarshalIndent(item, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n  "
+	if w.total < 1 {
+		sep = "[\n  "
+	}
+	w.total = w.total + 1
+
+	if _, err := w.buf.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = w.buf.Write(content)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.total < 1 {
+		end = "[]\n"
+	}
+	w.buf.WriteString(end)
+
+	return closeAll(w.buf.Flush(), w.out)
+}
+
+// jsonlWriter writes every item as json on its own line
+type jsonlWriter struct {
+	out io.WriteCloser
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(item any) error {
+	return w.enc.Encode(item)
+}
+
+func (w *jsonlWriter) Close() error {
+	return closeAll(nil, w.out)
+}
+
+// csvWriter writes every item as a row of its flattened json fields,
+// e.g "author.nickname". Items of one feed may differ in fields, e.g photo
+// and video posts, so rows are kept until Close and header is the union of
+// fields of every item
+type csvWriter struct {
+	out  io.WriteCloser
+	csv  *csv.Writer
+	rows []map[string]string
+}
+
+func (w *csvWriter) Write(item any) error {
+	content, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// keep big ids intact
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	fields := map[string]string{}
+	flatten("", value, fields)
+	w.rows = append(w.rows, fields)
+
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	return closeAll(w.flush(), w.out)
+}
+
+func (w *csvWriter) flush() error {
+	if len(w.rows) < 1 {
+		return nil
+	}
+
+	keys := map[string]struct{}{}
+	for _, fields := range w.rows {
+		for k := range fields {
+			keys[k] = struct{}{}
+		}
+	}
+	header := sortedKeys(keys)
+	if err := w.csv.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(header))
+	for _, fields := range w.rows {
+		for i, k := range header {
+			row[i] = fields[k]
+		}
+		if err := w.csv.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// flatten writes leaf values of json object into fields keyed by dotted path.
+// Arrays are kept as json
+func flatten(prefix string, value any, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(key, child, fields)
+		}
+	case []any:
+		content, _ := json.Marshal(v)
+		fields[prefix] = string(content)
+	case string:
+		fields[prefix] = v
+	case json.Number:
+		fields[prefix] = v.String()
+	case bool:
+		fields[prefix] = strconv.FormatBool(v)
+	case nil:
+		fields[prefix] = ""
+	default:
+		fields[prefix] = fmt.Sprint(v)
+	}
+}
+
+// closeAll closes out, returning err when it is not nil
+func closeAll(err error, out io.WriteCloser) error {
+	closeErr := out.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
@@ -0,0 +1,196 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// buffer is an output which records whether it is closed
+type buffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *buffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+type testAuthor struct {
+	Nickname string `json:"nickname"`
+}
+
+type testPost struct {
+	ID        string      `json:"id"`
+	Views     int64       `json:"views"`
+	Author    testAuthor  `json:"author"`
+	Tags      []string    `json:"tags,omitempty"`
+	ImagePost *testImages `json:"imagePost,omitempty"`
+}
+
+type testImages struct {
+	Total int `json:"total"`
+}
+
+func TestFlatten(t *testing.T) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewBufferString(`{"id":"7426139254123456789","views":12,"private":false,"author":{"nickname":"a","avatar":null},"tags":["x","y"]}`))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]string{}
+	flatten("", value, fields)
+
+	want := map[string]string{
+		"id":              "7426139254123456789",
+		"views":           "12",
+		"private":         "false",
+		"author.nickname": "a",
+		"author.avatar":   "",
+		"tags":            `["x","y"]`,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got %v, want %v", fields, want)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := &buffer{}
+	w, err := newWriter("csv", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first item has no image post, its columns come from the second item
+	items := []any{
+		testPost{ID: "1", Views: 10, Author: testAuthor{Nickname: "a"}},
+		testPost{ID: "2", Author: testAuthor{Nickname: "b, c"}, ImagePost: &testImages{Total: 3}},
+	}
+	for _, item := range items {
+		if err := w.Write(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "author.nickname,id,imagePost.total,views\n" +
+		"a,1,,10\n" +
+		"\"b, c\",2,3,0\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+	if !out.closed {
+		t.Error("output is not closed")
+	}
+}
+
+func TestCSVWriterEmpty(t *testing.T) {
+	out := &buffer{}
+	w, _ := newWriter("csv", out)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() > 0 {
+		t.Errorf("got %q, want empty output", out.String())
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []any
+	}{
+		{name: "empty"},
+		{name: "single", items: []any{testPost{ID: "1"}}},
+		{name: "multiple", items: []any{testPost{ID: "1"}, testPost{ID: "2", Tags: []string{"x"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &buffer{}
+			w, _ := newWriter("json", out)
+			for _, item := range tt.items {
+				if err := w.Write(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []testPost
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("invalid json %q: %v", out.String(), err)
+			}
+			if len(got) != len(tt.items) {
+				t.Fatalf("got %d items, want %d", len(got), len(tt.items))
+			}
+			for i, item := range tt.items {
+				if !reflect.DeepEqual(got[i], item) {
+					t.Errorf("item %d: got %v, want %v", i, got[i], item)
+				}
+			}
+			if !out.closed {
+				t.Error("output is not closed")
+			}
+		})
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	out := &buffer{}
+	w, _ := newWriter("jsonl", out)
+	w.Write(testPost{ID: "1"})
+	w.Write(testPost{ID: "2"})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"1","views":0,"author":{"nickname":""}}` + "\n" +
+		`{"id":"2","views":0,"author":{"nickname":""}}` + "\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := newWriter("xml", &buffer{}); err == nil {
+		t.Error("want error of unknown format")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2025-01-31", want: time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local)},
+		{value: "2025-01-31 10:20:30", want: time.Date(2025, 1, 31, 10, 20, 30, 0, time.Local)},
+		{value: "2025-01-31T10:20:30Z", want: time.Date(2025, 1, 31, 10, 20, 30, 0, time.UTC)},
+		{value: "2025-01-31T10:20:30+07:00", want: time.Date(2025, 1, 31, 3, 20, 30, 0, time.UTC)},
+		{value: "31/01/2025", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTime(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTime(%q) err = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
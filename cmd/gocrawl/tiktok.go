@@ -0,0 +1,58 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/nandanurseptama/golang-crawler/server"
+	"github.com/nandanurseptama/golang-crawler/tiktok"
+)
+
+var tiktokFlags = map[string]flags{
+	"search": func(fs *flag.FlagSet, param *server.Param) {
+		fs.Func("tab", "search tab, video or photo, defaults to general", func(s string) error {
+			switch tab := tiktok.SearchTab(s); tab {
+			case tiktok.SearchTabVideos, tiktok.SearchTabPhotos:
+				param.Tab = tab
+			case tiktok.SearchTabLive:
+				return errors.New("live tab has no content, use search-live command")
+			default:
+				return fmt.Errorf("invalid tab %s", s)
+			}
+			return nil
+		})
+		fs.Func("published-within", "only content posted within days, one of 1, 7, 30, 90 or 180", func(s string) error {
+			days, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+			switch within := tiktok.PublishTime(days); within {
+			case tiktok.PublishTimeDay, tiktok.PublishTimeWeek, tiktok.PublishTimeMonth, tiktok.PublishTimeThreeMonths, tiktok.PublishTimeSixMonths:
+				param.PublishedWithin = within
+			default:
+				return fmt.Errorf("invalid published within %s", s)
+			}
+			return nil
+		})
+		fs.Func("sort", "search result order, relevance or likes", func(s string) error {
+			switch s {
+			case "relevance":
+				param.SortBy = tiktok.SortByRelevance
+			case "likes":
+				param.SortBy = tiktok.SortByLikes
+			default:
+				return fmt.Errorf("invalid sort %s", s)
+			}
+			return nil
+		})
+	},
+	"comments": func(fs *flag.FlagSet, param *server.Param) {
+		fs.BoolVar(&param.Replies, "replies", false, "expand reply thread of every comment")
+		fs.UintVar(&param.MaxReplies, "max-replies", 0, "maximum replies of every thread, zero means unlimited")
+	},
+}
@@ -0,0 +1,22 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/server"
+)
+
+var youtubeFlags = map[string]flags{
+	"search":   delayFlag,
+	"channels": delayFlag,
+	"videos":   delayFlag,
+	"comments": delayFlag,
+}
+
+func delayFlag(fs *flag.FlagSet, param *server.Param) {
+	fs.DurationVar(&param.Delay.Duration, "delay", 3*time.Second, "delay between scroll")
+}
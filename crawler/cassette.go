@@ -0,0 +1,179 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Cassette records intercepted api responses of a crawl into a directory,
+// so the same crawl can be replayed later without reaching the platform.
+//
+// Responses are saved as <name>-<sequence>.json, where name is derived
+// from the intercepted url pattern, initial page data as initial-<sequence>.json,
+// and the page document, stripped of its scripts, as page.html.
+//
+// On replay, the page is loaded from Host instead, e.g Serve serving page.html.
+// As the page has no script, the crawler calls the api itself on every
+// expected page, and api requests are fulfilled from the directory.
+// Api called by actions other than scroll and expansion, e.g on reload,
+// is not replayed. A cassette serves a single crawl.
+type Cassette struct {
+	// Directory of recorded responses
+	Dir string
+	// Fulfil requests from Dir instead of recording them
+	Replay bool
+	// Optional, page host used on replay, e.g "http://127.0.0.1:8080"
+	Host string
+
+	mu       sync.Mutex
+	sequence map[string]int
+}
+
+func NewCassette(dir string, replay bool) *Cassette {
+	return &Cassette{
+		Dir:    dir,
+		Replay: replay,
+	}
+}
+
+// Serve starts a local server serving page.html of Dir on every path,
+// and uses it as replay Host. Caller should close the server.
+func (c *Cassette) Serve() *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join(c.Dir, "page.html"))
+	}))
+	c.Host = server.URL
+
+	return server
+}
+
+// next returns file path of the next response of name
+func (c *Cassette) next(name string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sequence == nil {
+		c.sequence = map[string]int{}
+	}
+	seq := c.sequence[name]
+	c.sequence[name] = seq + 1
+
+	return filepath.Join(c.Dir, fmt.Sprintf("%s-%04d.json", name, seq))
+}
+
+func (c *Cassette) record(name string, body []byte) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("cassette err : %w", err)
+	}
+
+	if err := os.WriteFile(c.next(name), body, 0o644); err != nil {
+		return fmt.Errorf("cassette err : %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cassette) play(name string) ([]byte, error) {
+	body, err := os.ReadFile(c.next(name))
+	if err != nil {
+		return nil, fmt.Errorf("cassette err : %w", err)
+	}
+
+	return body, nil
+}
+
+// pageURL returns uri served from Host on replay
+func (c *Cassette) pageURL(uri string) (string, error) {
+	if !c.Replay || c.Host == "" {
+		return uri, nil
+	}
+
+	page, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("cassette err : %w", err)
+	}
+	host, err := url.Parse(c.Host)
+	if err != nil {
+		return "", fmt.Errorf("cassette err : %w", err)
+	}
+
+	page.Scheme = host.Scheme
+	page.Host = host.Host
+
+	return page.String(), nil
+}
+
+// savePage saves document of the page as page.html of Dir,
+// without scripts nor external resources, so it loads offline on replay
+func (c *Cassette) savePage() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var html string
+		err := chromedp.Evaluate(`(() => {
+	const page = document.documentElement.cloneNode(true);
+	page.querySelectorAll('script, link, iframe, noscript').forEach(e => e.remove());
+	return '<!DOCTYPE html>\n' + page.outerHTML;
+})()`, &html).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("cassette err : %w", err)
+		}
+
+		if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+			return fmt.Errorf("cassette err : %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(c.Dir, "page.html"), []byte(html), 0o644); err != nil {
+			return fmt.Errorf("cassette err : %w", err)
+		}
+
+		return nil
+	})
+}
+
+// request makes the replayed page call an api matching pattern,
+// in place of the page scripts stripped by savePage
+func (c *Cassette) request(pattern string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		uri, err := json.Marshal(cassetteURL(pattern))
+		if err != nil {
+			return fmt.Errorf("cassette err : %w", err)
+		}
+
+		return chromedp.Evaluate(fmt.Sprintf(`void fetch(%s).catch(() => {})`, uri), nil).Do(ctx)
+	})
+}
+
+// cassetteURL returns url matching pattern relative to the page host,
+// e.g "*/post/item_list/*" to "/post/item_list/"
+func cassetteURL(pattern string) string {
+	uri := strings.NewReplacer("*", "", "?", "_").Replace(pattern)
+	if !strings.Contains(uri, "://") && !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri
+	}
+
+	return uri
+}
+
+// cassetteName returns file name of url pattern, e.g "*/post/item_list/*" to "post_item_list"
+func cassetteName(pattern string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, pattern)
+
+	return strings.Trim(name, "_")
+}
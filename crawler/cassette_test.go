@@ -0,0 +1,102 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"testing"
+)
+
+func TestCassetteName(t *testing.T) {
+	tests := map[string]string{
+		"*/post/item_list/*":         "post_item_list",
+		"*youtubei/v1/search*":       "youtubei_v1_search",
+		"*/api/comment/list/reply/*": "api_comment_list_reply",
+		"*/api-live/user/room/*":     "api_live_user_room",
+	}
+
+	for pattern, want := range tests {
+		if got := cassetteName(pattern); got != want {
+			t.Errorf("cassetteName(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestCassetteURL(t *testing.T) {
+	patterns := []string{
+		"*/post/item_list/*",
+		"*youtubei/v1/next*",
+		"*/api/comment/list/reply/*",
+		"*/api-live/user/room/*",
+	}
+
+	for _, pattern := range patterns {
+		uri := "http://127.0.0.1:8080" + cassetteURL(pattern)
+		if !matchPattern(pattern, uri) {
+			t.Errorf("cassetteURL(%q) = %q does not match its pattern", pattern, uri)
+		}
+	}
+
+	// replayed parent request must not be taken as its children
+	if uri := "http://127.0.0.1" + cassetteURL("*/api/comment/list/*"); matchPattern("*/api/comment/list/reply/*", uri) {
+		t.Errorf("cassetteURL of parent %q matches children pattern", uri)
+	}
+}
+
+func TestCassetteRecordAndPlay(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder := NewCassette(dir, false)
+	for _, body := range []string{`{"page":0}`, `{"page":1}`} {
+		if err := recorder.record("post_item_list", []byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recorder.record("initial", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	player := NewCassette(dir, true)
+	for _, want := range []string{`{"page":0}`, `{"page":1}`} {
+		body, err := player.play("post_item_list")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != want {
+			t.Fatalf("play() = %s, want %s", body, want)
+		}
+	}
+	if _, err := player.play("post_item_list"); err == nil {
+		t.Fatal("play() beyond recorded responses returns no error")
+	}
+	if body, err := player.play("initial"); err != nil || string(body) != `{}` {
+		t.Fatalf("play() of initial = %s %v", body, err)
+	}
+}
+
+func TestCassettePageURL(t *testing.T) {
+	cassette := NewCassette(t.TempDir(), true)
+
+	uri, err := cassette.pageURL("https://tiktok.com/search?q=cat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri != "https://tiktok.com/search?q=cat" {
+		t.Fatalf("pageURL() without host = %q", uri)
+	}
+
+	cassette.Host = "http://127.0.0.1:8080"
+	uri, err = cassette.pageURL("https://tiktok.com/search?q=cat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri != "http://127.0.0.1:8080/search?q=cat" {
+		t.Fatalf("pageURL() = %q", uri)
+	}
+
+	// recording cassette never swaps host
+	cassette.Replay = false
+	if uri, _ := cassette.pageURL("https://tiktok.com/@user"); uri != "https://tiktok.com/@user" {
+		t.Fatalf("pageURL() of recording cassette = %q", uri)
+	}
+}
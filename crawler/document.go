@@ -0,0 +1,78 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Document crawls data embedded on a single page, e.g hydration json,
+// without scrolling nor intercepting any api
+type Document[T any] struct {
+	// Browser config used to open a tab for every fetch
+	Config Config
+	// Name of crawled platform, used on error message
+	Platform string
+	// Page url to crawl
+	URL string
+	// Selector of element visible once the page is ready
+	ReadySelector string
+	// Optional, inspects the page for blocking state while waiting it ready
+	// and once it is ready, e.g returns ErrCaptcha when captcha is shown
+	Check func(ctx context.Context) error
+
+	// Name of data, used on cassette and error message, e.g "user_detail"
+	Name string
+	// Expression of data embedded on the page
+	Script string
+	// Decode data evaluated from Script
+	Decode func(body []byte) (T, error)
+}
+
+// Fetch crawls the page on a new browser tab and returns its decoded data
+func (d *Document[T]) Fetch(ctx context.Context) (T, error) {
+	var result T
+
+	tabCtx, cancel, err := d.Config.NewContext(ctx)
+	if err != nil {
+		return result, wrapError(d.Platform, d.Name, err, context.Cause(ctx))
+	}
+	defer cancel()
+	ctx = tabCtx
+
+	uri := d.URL
+	if cassette := d.Config.Cassette; cassette != nil {
+		uri, err = cassette.pageURL(uri)
+		if err != nil {
+			return result, wrapError(d.Platform, d.Name, err, nil)
+		}
+	}
+
+	tasks := chromedp.Tasks{
+		EnableFetch(),
+		chromedp.Navigate(uri),
+	}
+	if d.ReadySelector != "" {
+		tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+			return waitReady(ctx, d.ReadySelector, d.Check)
+		}))
+	}
+	if d.Check != nil {
+		tasks = append(tasks, chromedp.ActionFunc(d.Check))
+	}
+	if cassette := d.Config.Cassette; cassette != nil && !cassette.Replay {
+		tasks = append(tasks, cassette.savePage())
+	}
+	tasks = append(tasks, EvaluateJSON(d.Config.Cassette, d.Name, d.Script, func(body []byte) error {
+		result, err = d.Decode(body)
+		return err
+	}))
+
+	err = wrapError(d.Platform, d.Name, chromedp.Run(ctx, tasks), context.Cause(ctx))
+	Report(ctx, err)
+
+	return result, err
+}
@@ -0,0 +1,107 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Kind of crawl failure, use it with errors.Is
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrPrivate       = errors.New("private")
+	ErrBlocked       = errors.New("blocked")
+	ErrCaptcha       = errors.New("captcha required")
+	ErrSchemaChanged = errors.New("response schema changed")
+	ErrTimeout       = errors.New("timeout")
+	ErrLoginRequired = errors.New("login required")
+	// Cookie consent page shown instead of content, e.g on EU traffic.
+	// Unlike ErrBlocked, the proxy is still usable
+	ErrConsentRequired = errors.New("consent required")
+)
+
+// Error describes failure of crawling a platform endpoint
+type Error struct {
+	// Crawled platform, e.g tiktok
+	Platform string
+	// Crawled endpoint, e.g intercepted url pattern
+	Endpoint string
+	// Raw status code returned by platform api, zero when unknown
+	StatusCode int
+	// One of kind errors, e.g ErrCaptcha. nil when unknown
+	Kind error
+	// Underlying error
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s crawler err : %s", e.Platform, e.Endpoint)
+	if e.Kind != nil {
+		msg = fmt.Sprintf("%s : %s", msg, e.Kind.Error())
+	}
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("%s : status code %d", msg, e.StatusCode)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s : %s", msg, e.Err.Error())
+	}
+
+	return msg
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError returns crawl error of kind
+func NewError(platform string, endpoint string, kind error, err error) *Error {
+	return &Error{
+		Platform: platform,
+		Endpoint: endpoint,
+		Kind:     kind,
+		Err:      err,
+	}
+}
+
+// wrapError returns err as *Error, filling its platform and endpoint when missing.
+// cause is the cancellation cause of crawling context, if any
+func wrapError(platform string, endpoint string, err error, cause error) error {
+	if err == nil {
+		return nil
+	}
+
+	var crawlErr *Error
+	if errors.As(err, &crawlErr) {
+		if crawlErr.Platform == "" {
+			crawlErr.Platform = platform
+		}
+		if crawlErr.Endpoint == "" {
+			crawlErr.Endpoint = endpoint
+		}
+		return crawlErr
+	}
+
+	return NewError(platform, endpoint, errorKind(err, cause), err)
+}
+
+func errorKind(err error, cause error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(cause, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return ErrSchemaChanged
+	}
+
+	return nil
+}
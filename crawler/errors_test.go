@@ -0,0 +1,58 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWrapError(t *testing.T) {
+	var v struct{ Count int }
+	schemaErr := json.Unmarshal([]byte(`{"Count":"1"}`), &v)
+
+	tests := []struct {
+		name  string
+		err   error
+		cause error
+		kind  error
+	}{
+		{"deadline", context.DeadlineExceeded, nil, ErrTimeout},
+		{"deadline cause", context.Canceled, context.DeadlineExceeded, ErrTimeout},
+		{"schema", schemaErr, nil, ErrSchemaChanged},
+		{"unknown", errors.New("boom"), nil, nil},
+	}
+
+	for _, tt := range tests {
+		err := wrapError("tiktok", "*/post/item_list/*", tt.err, tt.cause)
+
+		var crawlErr *Error
+		if !errors.As(err, &crawlErr) {
+			t.Fatalf("%s : wrapError() = %T, want *Error", tt.name, err)
+		}
+		if crawlErr.Kind != tt.kind || crawlErr.Platform != "tiktok" || crawlErr.Endpoint != "*/post/item_list/*" {
+			t.Errorf("%s : wrapError() = %+v", tt.name, crawlErr)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s : wrapError() does not wrap %v", tt.name, tt.err)
+		}
+	}
+
+	if err := wrapError("tiktok", "", nil, nil); err != nil {
+		t.Errorf("wrapError(nil) = %v", err)
+	}
+}
+
+func TestWrapErrorKeepsKind(t *testing.T) {
+	err := wrapError("tiktok", "*/post/item_list/*", NewError("", "", ErrCaptcha, nil), nil)
+
+	if !errors.Is(err, ErrCaptcha) || errors.Is(err, ErrBlocked) {
+		t.Fatalf("wrapError() = %v, want ErrCaptcha only", err)
+	}
+	if want := "tiktok crawler err : */post/item_list/* : captcha required"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"time"
+)
+
+// Expansion loads children of an item by acting on the page,
+// e.g clicking "view replies" of a comment, and intercepting their api.
+// Children are yielded right after their item.
+type Expansion[T any] struct {
+	// Url pattern of intercepted children api, e.g "*/api/comment/list/reply/*"
+	Pattern string
+	// Decode intercepted children api response body
+	Decode func(body []byte) (items []T, hasMore bool, err error)
+	// Reports whether item has children to load
+	Has func(item T) bool
+	// Triggers next children page of item, index is its position among parent items.
+	// It reports false when there is nothing to trigger
+	Load func(ctx context.Context, index int, item T) (bool, error)
+	// Optional, maximum children of every item, zero means unlimited
+	MaxItems uint
+	// Optional, how long to wait children api after Load, defaults to 10 seconds
+	Timeout time.Duration
+}
+
+// expand yields children of item and reports whether crawling goes on
+func (p *Paginator[T]) expand(ctx context.Context, children <-chan page[T], index int, item T, yield func(T) bool) (bool, error) {
+	timeout := p.Expand.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	var total uint
+	for {
+		// drop children loaded too late for previous item
+		select {
+		case <-children:
+		default:
+		}
+
+		loaded, err := p.Expand.Load(ctx, index, item)
+		if err != nil {
+			return false, err
+		}
+		if !loaded {
+			return true, nil
+		}
+		if err := p.replayRequest(ctx, p.Expand.Pattern); err != nil {
+			return false, err
+		}
+
+		var current page[T]
+		select {
+		case current = <-children:
+		case <-time.After(timeout):
+			// nothing loaded, move on to the next item
+			return true, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		if current.err != nil {
+			return false, current.err
+		}
+
+		for _, child := range current.items {
+			if !yield(child) {
+				return false, nil
+			}
+
+			total = total + 1
+			if p.Expand.MaxItems > 0 && total >= p.Expand.MaxItems {
+				return true, nil
+			}
+		}
+
+		if !current.hasMore {
+			return true, nil
+		}
+
+		select {
+		case <-time.After(p.Delay):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// newRepliesExpansion loads replies of every item by fetching their api from the page
+func newRepliesExpansion() *Expansion[string] {
+	cursors := map[string]int{}
+
+	return &Expansion[string]{
+		Pattern: "*/api/replies/*",
+		Decode: func(body []byte) ([]string, bool, error) {
+			var resp itemsResp
+			err := json.Unmarshal(body, &resp)
+			return resp.Items, resp.HasMore, err
+		},
+		Has: func(item string) bool {
+			return !strings.Contains(item, "/reply-")
+		},
+		Load: func(ctx context.Context, index int, item string) (bool, error) {
+			cursor := cursors[item]
+			cursors[item] = cursor + 1
+
+			script := fmt.Sprintf(`void fetch('/api/replies/?item=%s&cursor=%d')`, item, cursor)
+			return true, chromedp.Evaluate(script, nil).Do(ctx)
+		},
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestPaginatorExpand(t *testing.T) {
+	config := testConfig(t)
+	platform := newPlatform()
+	defer platform.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	paginator := newItemsPaginator(config, platform.URL+"/")
+	paginator.Scroll = 0
+	paginator.Expand = newRepliesExpansion()
+	items, err := paginator.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"item-0-0", "item-0-0/reply-0", "item-0-0/reply-1", "item-0-0/reply-2", "item-0-0/reply-3",
+		"item-0-1", "item-0-1/reply-0", "item-0-1/reply-1", "item-0-1/reply-2", "item-0-1/reply-3",
+	}
+	if !slices.Equal(items, want) {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+}
+
+func TestPaginatorExpandMaxItems(t *testing.T) {
+	config := testConfig(t)
+	platform := newPlatform()
+	defer platform.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// children are bounded by Expansion.MaxItems only
+	paginator := newItemsPaginator(config, platform.URL+"/")
+	paginator.MaxItems = 2
+	paginator.Expand = newRepliesExpansion()
+	paginator.Expand.MaxItems = 3
+	items, err := paginator.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"item-0-0", "item-0-0/reply-0", "item-0-0/reply-1", "item-0-0/reply-2",
+		"item-0-1", "item-0-1/reply-0", "item-0-1/reply-1", "item-0-1/reply-2",
+	}
+	if !slices.Equal(items, want) {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	cdppage "github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// job holds per job settings of a browser tab
+type job struct {
+	// proxy as returned by provider
+	proxy         string
+	proxyServer   string
+	proxyUsername string
+	proxyPassword string
+	provider      ProxyProvider
+
+	session         *Session
+	sessionProvider SessionProvider
+}
+
+type jobKey struct{}
+
+func jobFromContext(ctx context.Context) *job {
+	j, _ := ctx.Value(jobKey{}).(*job)
+	return j
+}
+
+// newJob resolves settings of a crawl job from config
+func (param *Config) newJob(ctx context.Context) (*job, error) {
+	j := &job{
+		proxy:           param.Proxy,
+		provider:        param.ProxyProvider,
+		sessionProvider: param.SessionProvider,
+	}
+
+	if param.SessionProvider != nil {
+		session, err := param.SessionProvider.Session(ctx)
+		if err != nil {
+			return nil, err
+		}
+		j.session = session
+	}
+
+	if param.ProxyProvider != nil {
+		proxy, err := param.ProxyProvider.Proxy(ctx)
+		if err != nil {
+			return nil, err
+		}
+		j.proxy = proxy
+	}
+
+	if j.proxy != "" {
+		var err error
+		j.proxyServer, j.proxyUsername, j.proxyPassword, err = parseProxy(j.proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return j, nil
+}
+
+// restoreSession loads session cookies and local storage into browser tab
+func (j *job) restoreSession() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if j.session == nil {
+			return nil
+		}
+
+		var cookies []*network.CookieParam
+		for _, c := range j.session.Cookies {
+			cookies = append(cookies, c.toParam())
+		}
+		if len(cookies) > 0 {
+			if err := network.SetCookies(cookies).Do(ctx); err != nil {
+				return fmt.Errorf("restore session err : %w", err)
+			}
+		}
+
+		if len(j.session.LocalStorage) < 1 {
+			return nil
+		}
+		storage, err := json.Marshal(j.session.LocalStorage)
+		if err != nil {
+			return fmt.Errorf("restore session err : %w", err)
+		}
+		// local storage is only reachable from its origin, so it is set on every document
+		script := fmt.Sprintf(`(() => {
+	const items = (%s)[location.origin];
+	if (!items) return;
+	for (const [key, value] of Object.entries(items)) {
+		if (localStorage.getItem(key) === null) localStorage.setItem(key, value);
+	}
+})()`, storage)
+		if _, err := cdppage.AddScriptToEvaluateOnNewDocument(script).Do(ctx); err != nil {
+			return fmt.Errorf("restore session err : %w", err)
+		}
+
+		return nil
+	})
+}
+
+// saveSession persists cookies and local storage of browser tab into session.
+// Nothing is saved once tab context is done. Failure is logged,
+// because it happens while the tab is closed after the crawl
+func (j *job) saveSession(ctx context.Context) {
+	if j.session == nil || j.sessionProvider == nil || ctx.Err() != nil {
+		return
+	}
+
+	saveCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var cookies []*network.Cookie
+	var origin string
+	var storage map[string]string
+	err := chromedp.Run(saveCtx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			cookies, err = network.GetCookies().Do(ctx)
+			return err
+		}),
+		chromedp.Evaluate(`location.origin`, &origin),
+		chromedp.Evaluate(`Object.assign({}, localStorage)`, &storage),
+	)
+	if err != nil {
+		slog.Warn("failed to read session", slog.Any("err", err))
+		return
+	}
+
+	var saved []Cookie
+	for _, c := range cookies {
+		saved = append(saved, cookieFromNetwork(c))
+	}
+	j.session.merge(saved)
+
+	if strings.HasPrefix(origin, "http") {
+		if j.session.LocalStorage == nil {
+			j.session.LocalStorage = map[string]map[string]string{}
+		}
+		j.session.LocalStorage[origin] = storage
+	}
+
+	if err := j.sessionProvider.Save(ctx, j.session); err != nil {
+		slog.Warn("failed to save session", slog.Any("err", err))
+	}
+}
+
+// Report reports crawl result of browser tab context,
+// proxy of the tab is marked bad when it got blocked
+func Report(ctx context.Context, err error) {
+	j := jobFromContext(ctx)
+	if j == nil || err == nil {
+		return
+	}
+
+	if j.provider != nil && j.proxy != "" && (errors.Is(err, ErrBlocked) || errors.Is(err, ErrCaptcha)) {
+		j.provider.MarkBad(j.proxy, err)
+	}
+}
+
+// EnableFetch enables request interception of patterns on browser tab.
+//
+// When the tab proxy needs authentication, every request is paused
+// to answer its auth challenge. Paused requests not matching patterns
+// are continued right away, the rest are left to caller.
+func EnableFetch(patterns ...*fetch.RequestPattern) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		j := jobFromContext(ctx)
+		if j == nil || j.proxyUsername == "" {
+			if len(patterns) < 1 {
+				return nil
+			}
+			return fetch.Enable().WithPatterns(patterns).Do(ctx)
+		}
+
+		chromedp.ListenTarget(ctx, func(ev any) {
+			switch ev := ev.(type) {
+			case *fetch.EventAuthRequired:
+				go j.continueWithAuth(ctx, ev)
+			case *fetch.EventRequestPaused:
+				if !IsResponseStage(ev) && !matchPatterns(patterns, ev) {
+					go func() {
+						c := chromedp.FromContext(ctx)
+						fetch.ContinueRequest(ev.RequestID).Do(cdp.WithExecutor(ctx, c.Target))
+					}()
+				}
+			}
+		})
+
+		all := append([]*fetch.RequestPattern{}, patterns...)
+		all = append(all, &fetch.RequestPattern{
+			URLPattern:   "*",
+			RequestStage: fetch.RequestStageRequest,
+		})
+
+		return fetch.Enable().WithHandleAuthRequests(true).WithPatterns(all).Do(ctx)
+	})
+}
+
+// IsResponseStage reports whether request is paused at response stage
+func IsResponseStage(ev *fetch.EventRequestPaused) bool {
+	return ev.ResponseStatusCode != 0 || ev.ResponseErrorReason != ""
+}
+
+func (j *job) continueWithAuth(ctx context.Context, ev *fetch.EventAuthRequired) {
+	c := chromedp.FromContext(ctx)
+	e := cdp.WithExecutor(ctx, c.Target)
+
+	response := &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseDefault}
+	if ev.AuthChallenge != nil && ev.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
+		response = &fetch.AuthChallengeResponse{
+			Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+			Username: j.proxyUsername,
+			Password: j.proxyPassword,
+		}
+	}
+
+	fetch.ContinueWithAuth(ev.RequestID, response).Do(e)
+}
+
+// matchPatterns reports whether paused request matches one of patterns at its stage
+func matchPatterns(patterns []*fetch.RequestPattern, ev *fetch.EventRequestPaused) bool {
+	stage := fetch.RequestStageRequest
+	if IsResponseStage(ev) {
+		stage = fetch.RequestStageResponse
+	}
+
+	for _, pattern := range patterns {
+		patternStage := pattern.RequestStage
+		if patternStage == "" {
+			patternStage = fetch.RequestStageRequest
+		}
+		if patternStage == stage && matchPattern(pattern.URLPattern, ev.Request.URL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPattern reports whether url matches fetch url pattern,
+// where '*' matches zero or more and '?' matches exactly one character
+func matchPattern(pattern string, url string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	matched, _ := regexp.MatchString(expr.String(), url)
+	return matched
+}
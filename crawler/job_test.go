@@ -0,0 +1,31 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"*/post/item_list/*", "https://www.tiktok.com/api/post/item_list/?count=35", true},
+		{"*/post/item_list/*", "https://www.tiktok.com/api/repost/item_list/?count=35", false},
+		{"*/post/item_list/*", "https://www.tiktok.com/api/post/item_list", false},
+		{"*youtubei/v1/search*", "https://www.youtube.com/youtubei/v1/search?prettyPrint=false", true},
+		{"*youtubei/v1/search*", "https://www.youtube.com/youtubei/v1/browse", false},
+		{"*/api/comment/list/*", "https://www.tiktok.com/api/comment/list/reply/?item_id=1", true},
+		{"https://a.com/?", "https://a.com/x", true},
+		{"https://a.com/?", "https://a.com/", false},
+		{"https://a.com/v1.2", "https://a.com/v1x2", false},
+		{"*", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.url); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
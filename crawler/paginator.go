@@ -0,0 +1,511 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"iter"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// Paginator crawls page that loads its items from an api while scrolling.
+// It intercepts every api response matching Pattern, decodes it
+// and scrolls the page until there is no more item or Scroll is reached.
+type Paginator[T any] struct {
+	// Browser config used to open a tab for every run
+	Config Config
+	// Name of crawled platform, used on error message
+	Platform string
+	// Page url to crawl
+	URL string
+	// Url pattern of intercepted api, e.g "*/search/general/full/*"
+	Pattern string
+	// Decode intercepted api response body
+	Decode func(body []byte) (items []T, hasMore bool, err error)
+	// Selector of element visible once the page is ready
+	ReadySelector string
+	// Script evaluated to load next page
+	ScrollScript string
+
+	// Total scroll after the first page, so Scroll+1 pages are loaded
+	Scroll uint
+	// Delay duration between scroll
+	Delay time.Duration
+	// Optional, maximum returned items, zero means unlimited.
+	// Children of Expand are not counted, see Expansion.MaxItems
+	MaxItems uint
+	// Optional, how long to wait every page, e.g when scroll may load nothing.
+	// Crawling ends without error once passed, zero waits until ctx is done
+	PageTimeout time.Duration
+	// Optional, reports whether crawling ends at item.
+	// The item itself is not returned
+	Stop func(item T) bool
+
+	// Optional, inspects the page for blocking state while waiting it ready,
+	// e.g returns ErrCaptcha when captcha is shown
+	Check func(ctx context.Context) error
+	// Optional, actions run once page is ready,
+	// e.g scroll into comment section to trigger first page
+	Actions []chromedp.Action
+	// Optional, Actions reload the item list, e.g select a category,
+	// so pages requested before them are dropped
+	Reload bool
+	// Optional, rewrites url of every api request matching Pattern
+	// before it is sent, e.g adds search filter to its query
+	Rewrite func(url string) string
+	// Optional, expression of first page data embedded on the document, e.g ytInitialData.
+	// When empty, first page comes from intercepted api
+	InitialScript string
+	// Decode first page data evaluated from InitialScript
+	DecodeInitial func(body []byte) (items []T, hasMore bool, err error)
+	// Optional, expands every item into its children, e.g comment replies
+	Expand *Expansion[T]
+}
+
+type page[T any] struct {
+	items   []T
+	hasMore bool
+	err     error
+	// order of intercepted request
+	seq uint64
+	// generation when the page was requested, see Paginator.Reload
+	generation uint64
+}
+
+// pageQueue hands intercepted pages out in request order,
+// although they are decoded concurrently
+type pageQueue[T any] struct {
+	pages   chan page[T]
+	pending map[uint64]page[T]
+	next    uint64
+	// bumped on reload, pages of older generation are dropped
+	generation atomic.Uint64
+}
+
+func newPageQueue[T any]() *pageQueue[T] {
+	return &pageQueue[T]{
+		pages:   make(chan page[T], 1),
+		pending: map[uint64]page[T]{},
+	}
+}
+
+// errNoPage is returned by receive when no page is loaded within timeout
+var errNoPage = errors.New("no page loaded")
+
+// receive waits the next page in request order, zero timeout waits until ctx is done
+func (q *pageQueue[T]) receive(ctx context.Context, timeout time.Duration) (page[T], error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		if current, ok := q.pending[q.next]; ok {
+			delete(q.pending, q.next)
+			q.next = q.next + 1
+			if current.generation < q.generation.Load() {
+				continue
+			}
+			return current, nil
+		}
+
+		select {
+		case current := <-q.pages:
+			q.pending[current.seq] = current
+		case <-expired:
+			return page[T]{}, errNoPage
+		case <-ctx.Done():
+			return page[T]{}, ctx.Err()
+		}
+	}
+}
+
+// Run crawls the page on a new browser tab and calls yield
+// for every item in loaded order. Returning false from yield stops crawling.
+func (p *Paginator[T]) Run(ctx context.Context, yield func(T) bool) error {
+	// Browser context
+	tabCtx, cancel, err := p.Config.NewContext(ctx)
+	if err != nil {
+		return wrapError(p.Platform, p.Pattern, err, context.Cause(ctx))
+	}
+	defer cancel()
+	ctx = tabCtx
+
+	listenCtx, cancelListen := context.WithCancel(ctx)
+	defer cancelListen()
+
+	pages := newPageQueue[T]()
+	children := make(chan page[T], 1)
+	uri := p.URL
+	stage := fetch.RequestStageResponse
+	if cassette := p.Config.Cassette; cassette != nil && cassette.Replay {
+		uri, err = cassette.pageURL(uri)
+		if err != nil {
+			return wrapError(p.Platform, p.Pattern, err, nil)
+		}
+		// replayed requests never reach the network
+		stage = fetch.RequestStageRequest
+	}
+
+	pattern := &fetch.RequestPattern{URLPattern: p.Pattern, RequestStage: stage}
+	patterns := []*fetch.RequestPattern{pattern}
+	var rewritePattern *fetch.RequestPattern
+	if p.Rewrite != nil && stage == fetch.RequestStageResponse {
+		rewritePattern = &fetch.RequestPattern{URLPattern: p.Pattern, RequestStage: fetch.RequestStageRequest}
+		patterns = append(patterns, rewritePattern)
+	}
+	var childPattern *fetch.RequestPattern
+	if p.Expand != nil {
+		childPattern = &fetch.RequestPattern{URLPattern: p.Expand.Pattern, RequestStage: stage}
+		patterns = append(patterns, childPattern)
+	}
+
+	// events are delivered one by one, so seq follows request order
+	var seq uint64
+	// generation of every sent request, keyed by its network id
+	generations := map[network.RequestID]uint64{}
+	chromedp.ListenTarget(
+		listenCtx, func(ev any) {
+			switch ev := ev.(type) {
+			case *network.EventRequestWillBeSent:
+				if matchPattern(p.Pattern, ev.Request.URL) {
+					generations[ev.RequestID] = pages.generation.Load()
+				}
+			case *fetch.EventRequestPaused:
+				// children api may also match Pattern, so it goes first
+				if childPattern != nil && matchPatterns([]*fetch.RequestPattern{childPattern}, ev) {
+					// children api matching Pattern is tracked as well
+					delete(generations, ev.NetworkID)
+					go p.intercept(listenCtx, ev, p.Expand.Pattern, p.Expand.Decode, 0, 0, children)
+				} else if rewritePattern != nil && matchPatterns([]*fetch.RequestPattern{rewritePattern}, ev) {
+					go p.rewrite(listenCtx, ev)
+				} else if matchPatterns([]*fetch.RequestPattern{pattern}, ev) {
+					generation, ok := generations[ev.NetworkID]
+					if !ok {
+						generation = pages.generation.Load()
+					}
+					delete(generations, ev.NetworkID)
+
+					go p.intercept(listenCtx, ev, p.Pattern, p.Decode, seq, generation, pages.pages)
+					seq = seq + 1
+				}
+			}
+		},
+	)
+
+	tasks := chromedp.Tasks{
+		network.Enable(),
+		EnableFetch(patterns...),
+		chromedp.Navigate(uri),
+	}
+	if p.ReadySelector != "" {
+		tasks = append(tasks, chromedp.ActionFunc(p.waitReady))
+	}
+	if p.Reload {
+		tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+			pages.generation.Add(1)
+			return nil
+		}))
+	}
+	tasks = append(tasks, p.Actions...)
+	if cassette := p.Config.Cassette; cassette != nil && !cassette.Replay {
+		tasks = append(tasks, cassette.savePage())
+	}
+	tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+		return p.paginate(ctx, pages, children, yield)
+	}))
+
+	err = wrapError(p.Platform, p.Pattern, chromedp.Run(ctx, tasks), context.Cause(ctx))
+	Report(ctx, err)
+
+	return err
+}
+
+// Collect crawls the page and returns every loaded item.
+// Items loaded before failure are returned together with the error.
+func (p *Paginator[T]) Collect(ctx context.Context) ([]T, error) {
+	var results []T
+	err := p.Run(ctx, func(item T) bool {
+		results = append(results, item)
+		return true
+	})
+
+	return results, err
+}
+
+// Seq crawls the page lazily, every item is yielded as soon as its page is intercepted.
+// Crawling error is yielded once, as the last element.
+func (p *Paginator[T]) Seq(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		stopped := false
+		err := p.Run(ctx, func(item T) bool {
+			stopped = !yield(item, nil)
+			return !stopped
+		})
+
+		if err != nil && !stopped {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+func (p *Paginator[T]) paginate(ctx context.Context, pages *pageQueue[T], children <-chan page[T], yield func(T) bool) error {
+	var current page[T]
+	if p.InitialScript != "" {
+		body, err := p.initial(ctx)
+		if err != nil {
+			return err
+		}
+		current.items, current.hasMore, current.err = p.DecodeInitial(body)
+	} else {
+		if err := p.replayRequest(ctx, p.Pattern); err != nil {
+			return err
+		}
+
+		var err error
+		current, err = pages.receive(ctx, p.PageTimeout)
+		if errors.Is(err, errNoPage) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var totalScroll uint
+	var totalItem uint
+	var index int
+	for {
+		if current.err != nil {
+			return current.err
+		}
+
+		for _, item := range current.items {
+			if p.Stop != nil && p.Stop(item) {
+				return nil
+			}
+			if !yield(item) {
+				return nil
+			}
+			totalItem = totalItem + 1
+
+			// children of the last item are still expanded
+			if p.Expand != nil && p.Expand.Has(item) {
+				next, err := p.expand(ctx, children, index, item, yield)
+				if err != nil || !next {
+					return err
+				}
+			}
+			if p.MaxItems > 0 && totalItem >= p.MaxItems {
+				return nil
+			}
+			index = index + 1
+		}
+
+		if !current.hasMore || totalScroll >= p.Scroll {
+			return nil
+		}
+
+		// give the page time to render loaded items
+		select {
+		case <-time.After(p.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		_, exp, err := runtime.Evaluate(p.ScrollScript).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exp != nil {
+			return exp
+		}
+		if err := p.replayRequest(ctx, p.Pattern); err != nil {
+			return err
+		}
+		totalScroll = totalScroll + 1
+
+		current, err = pages.receive(ctx, p.PageTimeout)
+		if errors.Is(err, errNoPage) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// replayRequest calls api of pattern on replay,
+// where the page has no script calling it while scrolling
+func (p *Paginator[T]) replayRequest(ctx context.Context, pattern string) error {
+	cassette := p.Config.Cassette
+	if cassette == nil || !cassette.Replay {
+		return nil
+	}
+
+	return cassette.request(pattern).Do(ctx)
+}
+
+// waitReady waits ReadySelector visible, while checking the page periodically
+func (p *Paginator[T]) waitReady(ctx context.Context) error {
+	return waitReady(ctx, p.ReadySelector, p.Check)
+}
+
+// waitReady waits selector visible, while checking the page every second when check is set
+func waitReady(ctx context.Context, selector string, check func(ctx context.Context) error) error {
+	if check == nil {
+		return chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
+	}
+
+	readyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ready := make(chan error, 1)
+	go func() {
+		ready <- chromedp.WaitVisible(selector, chromedp.ByQuery).Do(readyCtx)
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-ready:
+			return err
+		case <-ticker.C:
+			if err := check(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// initial returns first page data embedded on the document
+func (p *Paginator[T]) initial(ctx context.Context) ([]byte, error) {
+	var data []byte
+	err := EvaluateJSON(p.Config.Cassette, "initial", p.InitialScript, func(body []byte) error {
+		data = body
+		return nil
+	}).Do(ctx)
+
+	return data, err
+}
+
+// EvaluateJSON evaluates JSON.stringify of expression on the page and decodes its result,
+// e.g data embedded on the document. The result is recorded into cassette under name,
+// on replay it comes from cassette instead
+func EvaluateJSON(cassette *Cassette, name string, expression string, decode func(body []byte) error) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if cassette != nil && cassette.Replay {
+			body, err := cassette.play(name)
+			if err != nil {
+				return err
+			}
+			return decode(body)
+		}
+
+		var data string
+		err := chromedp.Evaluate(fmt.Sprintf(`JSON.stringify(%s)`, expression), &data).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		if cassette != nil {
+			if err := cassette.record(name, []byte(data)); err != nil {
+				return err
+			}
+		}
+
+		return decode([]byte(data))
+	})
+}
+
+func (p *Paginator[T]) intercept(
+	ctx context.Context,
+	ev *fetch.EventRequestPaused,
+	pattern string,
+	decode func(body []byte) ([]T, bool, error),
+	seq uint64,
+	generation uint64,
+	pages chan<- page[T],
+) {
+	c := chromedp.FromContext(ctx)
+	e := cdp.WithExecutor(ctx, c.Target)
+
+	result := page[T]{seq: seq, generation: generation}
+	body, err := p.responseBody(e, ev, pattern)
+	if err != nil {
+		result.err = err
+	} else {
+		result.items, result.hasMore, result.err = decode(body)
+	}
+
+	select {
+	case pages <- result:
+	case <-ctx.Done():
+	}
+}
+
+// rewrite continues paused api request with url returned by Rewrite
+func (p *Paginator[T]) rewrite(ctx context.Context, ev *fetch.EventRequestPaused) {
+	c := chromedp.FromContext(ctx)
+	e := cdp.WithExecutor(ctx, c.Target)
+
+	continueRequest := fetch.ContinueRequest(ev.RequestID)
+	if uri := p.Rewrite(ev.Request.URL); uri != ev.Request.URL {
+		continueRequest = continueRequest.WithURL(uri)
+	}
+	continueRequest.Do(e)
+}
+
+// responseBody returns body of intercepted api and lets the page receive it.
+// On replay, the body comes from cassette instead
+func (p *Paginator[T]) responseBody(ctx context.Context, ev *fetch.EventRequestPaused, pattern string) ([]byte, error) {
+	name := cassetteName(pattern)
+	cassette := p.Config.Cassette
+
+	if cassette != nil && cassette.Replay {
+		body, err := cassette.play(name)
+		if err != nil {
+			fetch.FailRequest(ev.RequestID, network.ErrorReasonFailed).Do(ctx)
+			return nil, err
+		}
+
+		err = fetch.FulfillRequest(ev.RequestID, 200).
+			WithResponseHeaders([]*fetch.HeaderEntry{{Name: "Content-Type", Value: "application/json"}}).
+			WithBody(base64.StdEncoding.EncodeToString(body)).
+			Do(ctx)
+
+		return body, err
+	}
+
+	body, err := fetch.GetResponseBody(ev.RequestID).Do(ctx)
+	// essential for trigger WaitVisible
+	if continueErr := fetch.ContinueResponse(ev.RequestID).Do(ctx); err == nil {
+		err = continueErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cassette != nil {
+		if err := cassette.record(name, body); err != nil {
+			return nil, err
+		}
+	}
+
+	return body, nil
+}
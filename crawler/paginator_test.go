@@ -0,0 +1,127 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPageQueueOrder(t *testing.T) {
+	queue := newPageQueue[int]()
+	ctx := context.Background()
+
+	// pages decoded out of request order
+	go func() {
+		for _, seq := range []uint64{2, 0, 1} {
+			queue.pages <- page[int]{items: []int{int(seq)}, seq: seq}
+		}
+	}()
+
+	for want := range 3 {
+		current, err := queue.receive(ctx, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current.items[0] != want {
+			t.Fatalf("receive() = page %d, want %d", current.items[0], want)
+		}
+	}
+}
+
+func TestPageQueueDropsOlderGeneration(t *testing.T) {
+	queue := newPageQueue[int]()
+	ctx := context.Background()
+
+	queue.generation.Add(1)
+	go func() {
+		queue.pages <- page[int]{items: []int{0}, seq: 0, generation: 0}
+		queue.pages <- page[int]{items: []int{1}, seq: 1, generation: 1}
+	}()
+
+	current, err := queue.receive(ctx, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.items[0] != 1 {
+		t.Fatalf("receive() = page %d, want page of current generation", current.items[0])
+	}
+}
+
+func TestPageQueueTimeout(t *testing.T) {
+	queue := newPageQueue[int]()
+
+	_, err := queue.receive(context.Background(), 10*time.Millisecond)
+	if !errors.Is(err, errNoPage) {
+		t.Fatalf("receive() error = %v, want errNoPage", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = queue.receive(ctx, 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("receive() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPaginatorScroll(t *testing.T) {
+	config := testConfig(t)
+	platform := newPlatform()
+	defer platform.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	tests := map[uint][]string{
+		0: {"item-0-0", "item-0-1"},
+		1: {"item-0-0", "item-0-1", "item-1-0", "item-1-1"},
+		// no more page after the third one
+		5: {"item-0-0", "item-0-1", "item-1-0", "item-1-1", "item-2-0", "item-2-1"},
+	}
+	for scroll, want := range tests {
+		paginator := newItemsPaginator(config, platform.URL+"/")
+		paginator.Scroll = scroll
+
+		items, err := paginator.Collect(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(items, want) {
+			t.Errorf("Scroll %d : items = %v, want %v", scroll, items, want)
+		}
+	}
+}
+
+func TestPaginatorStop(t *testing.T) {
+	config := testConfig(t)
+	platform := newPlatform()
+	defer platform.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	paginator := newItemsPaginator(config, platform.URL+"/")
+	paginator.MaxItems = 3
+	items, err := paginator.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"item-0-0", "item-0-1", "item-1-0"}; !slices.Equal(items, want) {
+		t.Errorf("MaxItems 3 : items = %v, want %v", items, want)
+	}
+
+	// stop item itself is not returned
+	paginator = newItemsPaginator(config, platform.URL+"/")
+	paginator.Stop = func(item string) bool { return item == "item-1-1" }
+	items, err = paginator.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"item-0-0", "item-0-1", "item-1-0"}; !slices.Equal(items, want) {
+		t.Errorf("Stop : items = %v, want %v", items, want)
+	}
+}
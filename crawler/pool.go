@@ -0,0 +1,345 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+var ErrPoolClosed = errors.New("browser pool is closed")
+
+// how long a browser may take to start
+const launchTimeout = 30 * time.Second
+
+type PoolOption struct {
+	// Total browser kept alive by the pool
+	Size int
+	// Maximum tabs opened at the same time on a single browser
+	TabsPerBrowser int
+	// Recycle browser after serving this many jobs,
+	// zero means browser is never recycled
+	MaxUses int
+}
+
+// BrowserPool owns a bounded set of long-lived browsers
+// and hands out isolated tabs to every crawl job
+type BrowserPool struct {
+	config Config
+	option PoolOption
+
+	mu       sync.Mutex
+	closed   bool
+	browsers []*pooledBrowser
+	// limit total opened tabs across browsers
+	slots chan struct{}
+	// starts a browser process, replaced on test
+	start func() (context.Context, context.CancelFunc, error)
+}
+
+type pooledBrowser struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// closed once the browser is launched, err is set when launch failed
+	ready chan struct{}
+	err   error
+	// closed once the browser process is gone
+	done chan struct{}
+
+	// total opened tab
+	active int
+	// total served job
+	uses int
+	// retired browser is closed once the last tab released
+	retired bool
+}
+
+func NewBrowserPool(config Config, option PoolOption) *BrowserPool {
+	if option.Size < 1 {
+		option.Size = 1
+	}
+	if option.TabsPerBrowser < 1 {
+		option.TabsPerBrowser = 1
+	}
+	// browser launched by pool must not ask the pool again
+	config.Pool = nil
+
+	pool := &BrowserPool{
+		config:   config,
+		option:   option,
+		browsers: make([]*pooledBrowser, option.Size),
+		slots:    make(chan struct{}, option.Size*option.TabsPerBrowser),
+	}
+	pool.start = pool.startBrowser
+
+	return pool
+}
+
+// NewContext opens a tab inside its own incognito browser context
+// on one of pool browsers. It blocks until a tab slot is free.
+//
+// Calling returned cancel closes the tab and gives the slot back to the pool.
+func (pool *BrowserPool) NewContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	j, err := pool.config.newJob(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pool.newContext(ctx, j)
+}
+
+func (pool *BrowserPool) newContext(ctx context.Context, j *job) (context.Context, context.CancelFunc, error) {
+	select {
+	case pool.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	b, err := pool.acquire(ctx)
+	if err != nil {
+		<-pool.slots
+		return nil, nil, fmt.Errorf("browser pool err : %w", err)
+	}
+
+	// tab lives on the browser, but dies with the job
+	jobCtx, jobCancel := context.WithCancelCause(b.ctx)
+	stop := context.AfterFunc(ctx, func() {
+		jobCancel(context.Cause(ctx))
+	})
+	// tabs sharing the profile can't have their own proxy
+	shareProfile := pool.config.UserDataDir != ""
+	if shareProfile && j.proxyServer != "" {
+		stop()
+		jobCancel(context.Canceled)
+		pool.release(b, false)
+		<-pool.slots
+		return nil, nil, errors.New("browser pool err : per job proxy can not be used with user data dir")
+	}
+	tabCtx, tabCancel := chromedp.NewContext(jobCtx, j.tabOptions(shareProfile)...)
+	tabCtx = context.WithValue(tabCtx, jobKey{}, j)
+
+	var once sync.Once
+	release := func(broken bool) {
+		once.Do(func() {
+			stop()
+			tabCancel()
+			jobCancel(context.Canceled)
+			pool.release(b, broken)
+			<-pool.slots
+		})
+	}
+
+	// open the tab now, so crashed browser is detected before the job starts
+	if err := chromedp.Run(tabCtx, j.restoreSession()); err != nil {
+		release(b.ctx.Err() != nil)
+		return nil, nil, fmt.Errorf("browser pool err : %w", err)
+	}
+
+	return tabCtx, func() {
+		j.saveSession(tabCtx)
+		release(b.ctx.Err() != nil)
+	}, nil
+}
+
+// Close shuts down every browser of the pool.
+// Running jobs will fail, because their browser is gone.
+func (pool *BrowserPool) Close() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.closed = true
+	for i, b := range pool.browsers {
+		if b == nil {
+			continue
+		}
+		b.retired = true
+		b.close()
+		pool.browsers[i] = nil
+	}
+}
+
+// acquire reserves a tab on one of pool browsers, waiting its launch when needed.
+// Browser is launched outside the lock, so a slow launch only blocks jobs waiting on it
+func (pool *BrowserPool) acquire(ctx context.Context) (*pooledBrowser, error) {
+	pool.mu.Lock()
+
+	if pool.closed {
+		pool.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	if pool.config.UserDataDir != "" && pool.option.Size > 1 {
+		pool.mu.Unlock()
+		return nil, errors.New("user data dir can not be shared by more than one browser")
+	}
+
+	// pick browser with the least opened tab
+	picked := -1
+	for i, b := range pool.browsers {
+		if b != nil && b.active >= pool.option.TabsPerBrowser {
+			continue
+		}
+		if picked < 0 || b == nil || (pool.browsers[picked] != nil && b.active < pool.browsers[picked].active) {
+			picked = i
+		}
+	}
+	if picked < 0 {
+		pool.mu.Unlock()
+		// slots guarantee there is always a free browser
+		return nil, errors.New("no browser available")
+	}
+
+	b := pool.browsers[picked]
+	var previous *pooledBrowser
+	crashed := b != nil && b.ctx != nil && b.ctx.Err() != nil
+	exhausted := b != nil && pool.option.MaxUses > 0 && b.uses >= pool.option.MaxUses
+	if crashed || exhausted {
+		pool.retire(picked)
+		previous = b
+		b = nil
+	}
+
+	if b == nil {
+		b = &pooledBrowser{
+			ready: make(chan struct{}),
+			done:  make(chan struct{}),
+		}
+		pool.browsers[picked] = b
+		// a profile can only be opened by one browser at a time
+		if pool.config.UserDataDir == "" {
+			previous = nil
+		}
+		go pool.launch(b, previous)
+	}
+
+	b.active = b.active + 1
+	b.uses = b.uses + 1
+	pool.mu.Unlock()
+
+	select {
+	case <-b.ready:
+	case <-ctx.Done():
+		pool.release(b, false)
+		return nil, ctx.Err()
+	}
+	if b.err != nil {
+		pool.release(b, false)
+		return nil, b.err
+	}
+
+	return b, nil
+}
+
+func (pool *BrowserPool) release(b *pooledBrowser, broken bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	b.active = b.active - 1
+	if broken {
+		for i, v := range pool.browsers {
+			if v == b {
+				pool.retire(i)
+			}
+		}
+	}
+
+	if b.retired && b.active < 1 {
+		b.close()
+	}
+}
+
+// retire detaches browser from the pool,
+// it keeps running until its last tab is released
+func (pool *BrowserPool) retire(index int) {
+	b := pool.browsers[index]
+	pool.browsers[index] = nil
+	if b == nil {
+		return
+	}
+
+	b.retired = true
+	if b.active < 1 {
+		b.close()
+	}
+}
+
+// launch starts browser b once previous browser is closed,
+// then wakes up every job waiting on b
+func (pool *BrowserPool) launch(b *pooledBrowser, previous *pooledBrowser) {
+	if previous != nil {
+		<-previous.done
+	}
+
+	ctx, cancel, err := pool.start()
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	defer close(b.ready)
+
+	if err == nil && pool.closed {
+		cancel()
+		err = ErrPoolClosed
+	}
+	if err != nil {
+		b.err = err
+		close(b.done)
+		// let next job launch a new browser
+		for i, v := range pool.browsers {
+			if v == b {
+				pool.browsers[i] = nil
+			}
+		}
+		return
+	}
+
+	b.ctx = ctx
+	b.cancel = cancel
+	if b.retired && b.active < 1 {
+		b.close()
+	}
+}
+
+func (pool *BrowserPool) startBrowser() (context.Context, context.CancelFunc, error) {
+	allocCtx, allocCancel, err := pool.config.newAllocator(context.Background(), "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := chromedp.NewContext(allocCtx)
+	// start browser process, hung launch is killed after launchTimeout
+	timer := time.AfterFunc(launchTimeout, cancel)
+	err = chromedp.Run(ctx)
+	if !timer.Stop() && err == nil {
+		err = context.DeadlineExceeded
+	}
+	if err != nil {
+		cancel()
+		allocCancel()
+		return nil, nil, fmt.Errorf("failed to launch browser : %w", err)
+	}
+
+	return ctx, func() {
+		cancel()
+		allocCancel()
+	}, nil
+}
+
+// close shuts down launched browser, it is called under pool lock
+func (b *pooledBrowser) close() {
+	if b.cancel == nil {
+		// still launching, launch closes it once done
+		return
+	}
+
+	select {
+	case <-b.done:
+	default:
+		b.cancel()
+		close(b.done)
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// fakeBrowsers stands in for browser processes launched by pool
+type fakeBrowsers struct {
+	mu       sync.Mutex
+	launched []context.Context
+	cancels  []context.CancelFunc
+	// launch blocks until gate is closed, when set
+	gate chan struct{}
+	err  error
+}
+
+func (f *fakeBrowsers) start() (context.Context, context.CancelFunc, error) {
+	if f.gate != nil {
+		<-f.gate
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.launched = append(f.launched, ctx)
+	f.cancels = append(f.cancels, cancel)
+
+	return ctx, cancel, nil
+}
+
+func (f *fakeBrowsers) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.launched)
+}
+
+func (f *fakeBrowsers) browser(i int) context.Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.launched[i]
+}
+
+func newFakePool(config Config, option PoolOption) (*BrowserPool, *fakeBrowsers) {
+	browsers := &fakeBrowsers{}
+	pool := NewBrowserPool(config, option)
+	pool.start = browsers.start
+
+	return pool, browsers
+}
+
+func mustAcquire(t *testing.T, pool *BrowserPool) *pooledBrowser {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	b, err := pool.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestBrowserPoolSpreadsTabs(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 2, TabsPerBrowser: 2})
+	defer pool.Close()
+
+	first := mustAcquire(t, pool)
+	second := mustAcquire(t, pool)
+	if first == second {
+		t.Fatal("second tab opens on busier browser")
+	}
+	third := mustAcquire(t, pool)
+	fourth := mustAcquire(t, pool)
+	if third == fourth || (third != first && third != second) || (fourth != first && fourth != second) {
+		t.Fatal("tabs are not spread across browsers")
+	}
+	if first.active != 2 || second.active != 2 || browsers.count() != 2 {
+		t.Fatalf("active = %d %d, launched = %d", first.active, second.active, browsers.count())
+	}
+
+	if _, err := pool.acquire(context.Background()); err == nil {
+		t.Fatal("acquire() beyond tab limit returns no error")
+	}
+
+	for _, b := range []*pooledBrowser{first, second, third, fourth} {
+		pool.release(b, false)
+	}
+	if first.active != 0 || second.active != 0 {
+		t.Fatalf("active = %d %d after release", first.active, second.active)
+	}
+	// idle browsers are kept alive
+	if browsers.browser(0).Err() != nil || browsers.browser(1).Err() != nil {
+		t.Fatal("idle browser is closed")
+	}
+}
+
+func TestBrowserPoolRecyclesAfterMaxUses(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 1, MaxUses: 2})
+	defer pool.Close()
+
+	for range 2 {
+		pool.release(mustAcquire(t, pool), false)
+	}
+	if browsers.count() != 1 {
+		t.Fatalf("launched = %d, want 1", browsers.count())
+	}
+
+	pool.release(mustAcquire(t, pool), false)
+	if browsers.count() != 2 {
+		t.Fatalf("launched = %d, want 2", browsers.count())
+	}
+	if browsers.browser(0).Err() == nil {
+		t.Fatal("exhausted browser is not closed")
+	}
+}
+
+func TestBrowserPoolRetiredBrowserServesOpenTabs(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 2, MaxUses: 1})
+	defer pool.Close()
+
+	first := mustAcquire(t, pool)
+	second := mustAcquire(t, pool)
+	if first == second || browsers.count() != 2 {
+		t.Fatalf("exhausted browser is reused, launched = %d", browsers.count())
+	}
+
+	// retired browser is closed once its last tab is released
+	if browsers.browser(0).Err() != nil {
+		t.Fatal("retired browser is closed while its tab is open")
+	}
+	pool.release(first, false)
+	if browsers.browser(0).Err() == nil {
+		t.Fatal("retired browser is not closed after its last tab")
+	}
+	pool.release(second, false)
+}
+
+func TestBrowserPoolReplacesCrashedBrowser(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 1})
+	defer pool.Close()
+
+	pool.release(mustAcquire(t, pool), false)
+	// browser process died while idle
+	browsers.cancels[0]()
+
+	b := mustAcquire(t, pool)
+	if browsers.count() != 2 || b.ctx != browsers.browser(1) {
+		t.Fatalf("crashed browser is reused, launched = %d", browsers.count())
+	}
+	pool.release(b, false)
+}
+
+func TestBrowserPoolRetiresBrokenBrowser(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 1})
+	defer pool.Close()
+
+	pool.release(mustAcquire(t, pool), true)
+	if browsers.browser(0).Err() == nil {
+		t.Fatal("broken browser is not closed")
+	}
+
+	pool.release(mustAcquire(t, pool), false)
+	if browsers.count() != 2 {
+		t.Fatalf("launched = %d, want 2", browsers.count())
+	}
+}
+
+func TestBrowserPoolLaunchFailure(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 1})
+	defer pool.Close()
+
+	browsers.err = errors.New("no chrome")
+	if _, err := pool.acquire(context.Background()); err == nil {
+		t.Fatal("acquire() of failed launch returns no error")
+	}
+
+	// failed browser is not kept, so the next job launches again
+	browsers.mu.Lock()
+	browsers.err = nil
+	browsers.mu.Unlock()
+	pool.release(mustAcquire(t, pool), false)
+	if browsers.count() != 1 {
+		t.Fatalf("launched = %d, want 1", browsers.count())
+	}
+}
+
+func TestBrowserPoolCancelWhileLaunching(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 1})
+	browsers.gate = make(chan struct{})
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := pool.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() = %v, want context.DeadlineExceeded", err)
+	}
+
+	pool.mu.Lock()
+	active := pool.browsers[0].active
+	pool.mu.Unlock()
+	if active != 0 {
+		t.Fatalf("active = %d after canceled acquire", active)
+	}
+
+	// launch goes on, and serves the next job
+	close(browsers.gate)
+	pool.release(mustAcquire(t, pool), false)
+	if browsers.count() != 1 {
+		t.Fatalf("launched = %d, want 1", browsers.count())
+	}
+}
+
+func TestBrowserPoolCloseWhileLaunching(t *testing.T) {
+	pool, browsers := newFakePool(Config{}, PoolOption{Size: 1, TabsPerBrowser: 1})
+	browsers.gate = make(chan struct{})
+
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := pool.acquire(context.Background())
+		acquired <- err
+	}()
+
+	// wait the launch is in flight
+	for {
+		pool.mu.Lock()
+		launching := pool.browsers[0] != nil
+		pool.mu.Unlock()
+		if launching {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	pool.Close()
+	close(browsers.gate)
+
+	if err := <-acquired; !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("acquire() = %v, want ErrPoolClosed", err)
+	}
+	if browsers.browser(0).Err() == nil {
+		t.Fatal("browser launched after Close is left running")
+	}
+	if _, err := pool.acquire(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("acquire() after Close = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestBrowserPoolUserDataDir(t *testing.T) {
+	pool, _ := newFakePool(Config{UserDataDir: t.TempDir()}, PoolOption{Size: 2})
+	defer pool.Close()
+
+	if _, err := pool.acquire(context.Background()); err == nil {
+		t.Fatal("acquire() of shared profile on two browsers returns no error")
+	}
+}
+
+func TestBrowserPoolUserDataDirWaitsPreviousBrowser(t *testing.T) {
+	pool, browsers := newFakePool(Config{UserDataDir: t.TempDir()}, PoolOption{Size: 1, TabsPerBrowser: 2, MaxUses: 1})
+	defer pool.Close()
+
+	first := mustAcquire(t, pool)
+
+	acquired := make(chan *pooledBrowser, 1)
+	go func() {
+		b, _ := pool.acquire(context.Background())
+		acquired <- b
+	}()
+
+	// replacement can't open the profile until the retired browser is closed
+	select {
+	case <-acquired:
+		t.Fatal("replacement browser launched while profile is still open")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if browsers.count() != 1 {
+		t.Fatalf("launched = %d, want 1", browsers.count())
+	}
+
+	pool.release(first, false)
+	second := <-acquired
+	if second == nil || second == first || browsers.count() != 2 {
+		t.Fatalf("launched = %d, want replacement browser", browsers.count())
+	}
+	pool.release(second, false)
+}
+
+func TestBrowserPoolNewContext(t *testing.T) {
+	config := testConfig(t)
+	pool := NewBrowserPool(config, PoolOption{Size: 1, TabsPerBrowser: 1, MaxUses: 2})
+	defer pool.Close()
+	config.Pool = pool
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// third job runs on recycled browser
+	for i := range 3 {
+		tabCtx, closeTab, err := config.NewContext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result int
+		err = chromedp.Run(tabCtx, chromedp.Evaluate(`1 + 1`, &result))
+		closeTab()
+		if err != nil {
+			t.Fatalf("job %d : %v", i, err)
+		}
+		if result != 2 {
+			t.Fatalf("job %d : result = %d", i, result)
+		}
+	}
+}
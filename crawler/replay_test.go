@@ -0,0 +1,168 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testConfig returns headless browser config of CHROME_PATH or chrome found on PATH,
+// the test is skipped when there is none
+func testConfig(t *testing.T) Config {
+	t.Helper()
+
+	path := os.Getenv("CHROME_PATH")
+	if path == "" {
+		for _, name := range []string{"google-chrome", "chromium", "chromium-browser", "headless-shell", "chrome"} {
+			if found, err := exec.LookPath(name); err == nil {
+				path = found
+				break
+			}
+		}
+	}
+	if path == "" {
+		t.Skip("chrome is not found, set CHROME_PATH to run browser tests")
+	}
+
+	return Config{ChromePath: path, Headless: true, DisableGPU: true, NoSandbox: true, DisableDevSHMUsage: true}
+}
+
+// platformPage loads items from api on load and on every scroll event
+const platformPage = `<!DOCTYPE html>
+<html>
+<body>
+<ul id="items"></ul>
+<script>
+let cursor = 0;
+let loading = false;
+async function load() {
+	if (loading || cursor < 0) return;
+	loading = true;
+	const resp = await fetch('/api/items/?cursor=' + cursor);
+	const data = await resp.json();
+	for (const item of data.items) {
+		const li = document.createElement('li');
+		li.textContent = item;
+		document.getElementById('items').appendChild(li);
+	}
+	cursor = data.hasMore ? cursor + 1 : -1;
+	loading = false;
+}
+window.addEventListener('scroll', load);
+load();
+</script>
+</body>
+</html>`
+
+type itemsResp struct {
+	Items   []string `json:"items"`
+	HasMore bool     `json:"hasMore"`
+}
+
+func newPlatform() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, platformPage)
+	})
+	mux.HandleFunc("/api/items/", func(w http.ResponseWriter, r *http.Request) {
+		cursor, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		json.NewEncoder(w).Encode(itemsResp{
+			Items:   []string{fmt.Sprintf("item-%d-0", cursor), fmt.Sprintf("item-%d-1", cursor)},
+			HasMore: cursor < 2,
+		})
+	})
+	// replies of item, two pages of two replies
+	mux.HandleFunc("/api/replies/", func(w http.ResponseWriter, r *http.Request) {
+		item := r.URL.Query().Get("item")
+		cursor, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		json.NewEncoder(w).Encode(itemsResp{
+			Items:   []string{fmt.Sprintf("%s/reply-%d", item, 2*cursor), fmt.Sprintf("%s/reply-%d", item, 2*cursor+1)},
+			HasMore: cursor < 1,
+		})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newItemsPaginator(config Config, uri string) *Paginator[string] {
+	return &Paginator[string]{
+		Config:   config,
+		Platform: "test",
+		URL:      uri,
+		Pattern:  "*/api/items/*",
+		Decode: func(body []byte) ([]string, bool, error) {
+			var resp itemsResp
+			err := json.Unmarshal(body, &resp)
+			return resp.Items, resp.HasMore, err
+		},
+		ReadySelector: "#items li",
+		// real page scripts are gone on replay, so scroll must not call them
+		ScrollScript: `window.dispatchEvent(new Event('scroll'))`,
+		Scroll:       5,
+		Delay:        300 * time.Millisecond,
+	}
+}
+
+func TestPaginatorRecordAndReplay(t *testing.T) {
+	config := testConfig(t)
+	dir := t.TempDir()
+	want := []string{"item-0-0", "item-0-1", "item-1-0", "item-1-1", "item-2-0", "item-2-1"}
+
+	platform := newPlatform()
+	uri := platform.URL + "/"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	recordConfig := config
+	recordConfig.Cassette = NewCassette(dir, false)
+	items, err := newItemsPaginator(recordConfig, uri).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(items, want) {
+		t.Fatalf("recorded items = %v, want %v", items, want)
+	}
+	platform.Close()
+
+	page, err := os.ReadFile(filepath.Join(dir, "page.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(page), "<script") || !strings.Contains(string(page), "item-0-0") {
+		t.Fatalf("recorded page keeps scripts or misses items : %s", page)
+	}
+	for i := range 3 {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("api_items-%04d.json", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// platform is closed, so everything comes from the cassette
+	cassette := NewCassette(dir, true)
+	server := cassette.Serve()
+	defer server.Close()
+
+	replayConfig := config
+	replayConfig.Cassette = cassette
+	items, err = newItemsPaginator(replayConfig, uri).Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(items, want) {
+		t.Fatalf("replayed items = %v, want %v", items, want)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// SessionProvider is consulted by every crawl job for logged-in session
+type SessionProvider interface {
+	// Session returns session of a job, nil session means the job is not logged in
+	Session(ctx context.Context) (*Session, error)
+	// Save persists session state after a job
+	Save(ctx context.Context, session *Session) error
+}
+
+// Session is browser state of a logged-in account
+type Session struct {
+	// Account name
+	Name    string   `json:"name"`
+	Cookies []Cookie `json:"cookies"`
+	// Local storage items keyed by origin, e.g "https://www.tiktok.com"
+	LocalStorage map[string]map[string]string `json:"localStorage"`
+}
+
+// Cookie of a session, follows json format exported by browser cookie extensions
+type Cookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain"`
+	Path   string `json:"path"`
+	// Expiration time in unix seconds, zero means session cookie
+	Expires  float64 `json:"expirationDate,omitempty"`
+	Secure   bool    `json:"secure"`
+	HTTPOnly bool    `json:"httpOnly"`
+	// One of "no_restriction", "lax" or "strict"
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+func (c Cookie) toParam() *network.CookieParam {
+	param := &network.CookieParam{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HTTPOnly: c.HTTPOnly,
+	}
+
+	switch strings.ToLower(c.SameSite) {
+	case "no_restriction", "none":
+		param.SameSite = network.CookieSameSiteNone
+	case "lax":
+		param.SameSite = network.CookieSameSiteLax
+	case "strict":
+		param.SameSite = network.CookieSameSiteStrict
+	}
+
+	if c.Expires > 0 {
+		sec, frac := math.Modf(c.Expires)
+		expires := cdp.TimeSinceEpoch(time.Unix(int64(sec), int64(frac*1e9)))
+		param.Expires = &expires
+	}
+
+	return param
+}
+
+func cookieFromNetwork(c *network.Cookie) Cookie {
+	cookie := Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HTTPOnly: c.HTTPOnly,
+	}
+	if !c.Session {
+		cookie.Expires = c.Expires
+	}
+
+	switch c.SameSite {
+	case network.CookieSameSiteNone:
+		cookie.SameSite = "no_restriction"
+	case network.CookieSameSiteLax:
+		cookie.SameSite = "lax"
+	case network.CookieSameSiteStrict:
+		cookie.SameSite = "strict"
+	}
+
+	return cookie
+}
+
+// merge replaces session cookies sharing name, domain and path with cookies
+func (s *Session) merge(cookies []Cookie) {
+	key := func(c Cookie) string {
+		return c.Name + ";" + c.Domain + ";" + c.Path
+	}
+
+	index := map[string]int{}
+	for i, c := range s.Cookies {
+		index[key(c)] = i
+	}
+
+	for _, c := range cookies {
+		if i, ok := index[key(c)]; ok {
+			s.Cookies[i] = c
+			continue
+		}
+		index[key(c)] = len(s.Cookies)
+		s.Cookies = append(s.Cookies, c)
+	}
+}
+
+// LoadCookies reads cookies from Netscape cookies.txt or json file
+func LoadCookies(path string) ([]Cookie, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load cookies err : %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(content)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var cookies []Cookie
+		if err := json.Unmarshal(trimmed, &cookies); err != nil {
+			return nil, fmt.Errorf("load cookies err : %w", err)
+		}
+		return cookies, nil
+	}
+
+	return parseNetscapeCookies(content)
+}
+
+// parseNetscapeCookies parses cookies.txt, every line is
+// domain, include subdomains, path, secure, expiration, name and value separated by tab
+func parseNetscapeCookies(content []byte) ([]Cookie, error) {
+	var cookies []Cookie
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		httpOnly := strings.HasPrefix(text, "#HttpOnly_")
+		if httpOnly {
+			text = strings.TrimPrefix(text, "#HttpOnly_")
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("load cookies err : invalid netscape cookie at line %d", line)
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("load cookies err : invalid expiration at line %d : %w", line, err)
+		}
+
+		cookies = append(cookies, Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Expires:  expires,
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("load cookies err : %w", err)
+	}
+
+	return cookies, nil
+}
+
+// SessionStore keeps every session as <name>.json on a directory,
+// and rotates them across jobs in round robin order
+type SessionStore struct {
+	Dir string
+
+	mu    sync.Mutex
+	names []string
+	next  int
+}
+
+// NewSessionStore returns store of every session already saved on dir
+func NewSessionStore(dir string) (*SessionStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("session store err : %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("session store err : %w", err)
+	}
+
+	store := &SessionStore{Dir: dir}
+	for _, file := range files {
+		store.names = append(store.names, strings.TrimSuffix(filepath.Base(file), ".json"))
+	}
+
+	return store, nil
+}
+
+// Import saves cookies file, see LoadCookies, as session of account name
+func (store *SessionStore) Import(name string, cookiesPath string) error {
+	cookies, err := LoadCookies(cookiesPath)
+	if err != nil {
+		return err
+	}
+
+	return store.Save(context.Background(), &Session{
+		Name:    name,
+		Cookies: cookies,
+	})
+}
+
+// Session returns the next stored session, nil when none is stored yet
+func (store *SessionStore) Session(ctx context.Context) (*Session, error) {
+	store.mu.Lock()
+	if len(store.names) < 1 {
+		store.mu.Unlock()
+		return nil, nil
+	}
+	name := store.names[store.next%len(store.names)]
+	store.next = store.next + 1
+	store.mu.Unlock()
+
+	content, err := os.ReadFile(store.path(name))
+	if err != nil {
+		return nil, fmt.Errorf("session store err : %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(content, &session); err != nil {
+		return nil, fmt.Errorf("session store err : %w", err)
+	}
+	session.Name = name
+
+	return &session, nil
+}
+
+func (store *SessionStore) Save(ctx context.Context, session *Session) error {
+	if session.Name == "" {
+		return errors.New("session store err : session has no name")
+	}
+
+	content, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("session store err : %w", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if err := os.WriteFile(store.path(session.Name), content, 0o600); err != nil {
+		return fmt.Errorf("session store err : %w", err)
+	}
+
+	for _, name := range store.names {
+		if name == session.Name {
+			return nil
+		}
+	}
+	store.names = append(store.names, session.Name)
+
+	return nil
+}
+
+func (store *SessionStore) path(name string) string {
+	return filepath.Join(store.Dir, filepath.Base(name)+".json")
+}
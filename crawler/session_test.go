@@ -0,0 +1,100 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package crawler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseNetscapeCookies(t *testing.T) {
+	content := "# Netscape HTTP Cookie File\n" +
+		"\n" +
+		".tiktok.com\tTRUE\t/\tTRUE\t1767225600\tsessionid\tabc\n" +
+		"#HttpOnly_.youtube.com\tTRUE\t/\tFALSE\t0\tSID\tdef\n"
+
+	cookies, err := parseNetscapeCookies([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Cookie{
+		{Name: "sessionid", Value: "abc", Domain: ".tiktok.com", Path: "/", Expires: 1767225600, Secure: true},
+		{Name: "SID", Value: "def", Domain: ".youtube.com", Path: "/", HTTPOnly: true},
+	}
+	if !reflect.DeepEqual(cookies, want) {
+		t.Fatalf("parseNetscapeCookies() = %+v, want %+v", cookies, want)
+	}
+}
+
+func TestParseNetscapeCookiesInvalid(t *testing.T) {
+	tests := []string{
+		".tiktok.com\tTRUE\t/\tTRUE\t1767225600\tsessionid\n",
+		".tiktok.com\tTRUE\t/\tTRUE\tnever\tsessionid\tabc\n",
+	}
+
+	for _, content := range tests {
+		if _, err := parseNetscapeCookies([]byte(content)); err == nil {
+			t.Errorf("parseNetscapeCookies(%q) returns no error", content)
+		}
+	}
+}
+
+func TestSessionStoreRotation(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"alice", "bob"} {
+		if err := store.Save(ctx, &Session{Name: name, Cookies: []Cookie{{Name: "sid", Value: name}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// sessions saved on dir are found by a new store
+	store, err = NewSessionStore(store.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"alice", "bob", "alice"} {
+		session, err := store.Session(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session.Name != want || session.Cookies[0].Value != want {
+			t.Fatalf("Session() = %+v, want %s", session, want)
+		}
+	}
+}
+
+func TestSessionStoreSaveWithoutName(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save(context.Background(), &Session{}); err == nil {
+		t.Fatal("Save() of session without name returns no error")
+	}
+}
+
+func TestNewJobWithEmptySessionStore(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first run has nothing stored yet, so the job is not logged in
+	config := Config{SessionProvider: store}
+	j, err := config.newJob(context.Background())
+	if err != nil {
+		t.Fatalf("newJob() = %v", err)
+	}
+	if j.session != nil {
+		t.Fatalf("job session = %+v, want nil", j.session)
+	}
+}
@@ -25,11 +25,32 @@ func main() {
 		NoSandbox:          true,
 		DisableDevSHMUsage: true,
 	}
+	// record intercepted responses, so the crawl can be replayed offline
+	if dir := os.Getenv("CASSETTE_DIR"); dir != "" {
+		config.Cassette = crawler.NewCassette(dir, false)
+	}
+	// crawl logged-in, sessions are imported with SessionStore.Import
+	if dir := os.Getenv("SESSION_DIR"); dir != "" {
+		store, err := crawler.NewSessionStore(dir)
+		if err != nil {
+			fmt.Println("failed open session store", err.Error())
+			return
+		}
+		config.SessionProvider = store
+	}
 	flags, _ := config.GetFlags()
 	for i, v := range flags {
 		fmt.Println(i, v)
 	}
 
+	// share browsers across jobs instead of launching chrome per call
+	config.Pool = crawler.NewBrowserPool(config, crawler.PoolOption{
+		Size:           1,
+		TabsPerBrowser: 3,
+		MaxUses:        50,
+	})
+	defer config.Pool.Close()
+
 	tiktokCrawler := tiktok.NewCrawler(config)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*3)
@@ -20,6 +20,12 @@ func main() {
 		Headless:           false,
 		DisableGPU:         true,
 		DisableDevSHMUsage: true,
+		// e.g browserless container "ws://localhost:3000"
+		RemoteURL: os.Getenv("REMOTE_URL"),
+	}
+	// record intercepted responses, so the crawl can be replayed offline
+	if dir := os.Getenv("CASSETTE_DIR"); dir != "" {
+		config.Cassette = crawler.NewCassette(dir, false)
 	}
 	flags, _ := config.GetFlags()
 	for i, v := range flags {
@@ -31,21 +37,23 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*3)
 	defer cancel()
 
-	results, err := youtubeCrawler.GetContentComments(ctx, youtube.SearchContentParam{
+	// comments are yielded as soon as every page is loaded
+	comments := youtubeCrawler.GetContentCommentsStream(ctx, youtube.SearchContentParam{
 		Term:                "r4-cftqTcdI",
 		Scroll:              3,
 		DelayScrollDuration: time.Second * 3,
 	})
 
-	if err != nil {
-		fmt.Println("failed get search content", err.Error())
-		return
-	}
-
-	slog.Info("results", slog.Any("length", len(results)))
+	var total int
+	for comment, err := range comments {
+		if err != nil {
+			fmt.Println("failed get content comments", err.Error())
+			return
+		}
 
-	for i, v := range results {
-		slog.Info("item at", slog.Any("index", i), slog.Any("value", v))
+		slog.Info("item at", slog.Any("index", total), slog.Any("value", comment))
+		total = total + 1
 	}
 
+	slog.Info("results", slog.Any("length", total))
 }
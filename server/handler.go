@@ -0,0 +1,159 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request : %w", err))
+		return
+	}
+
+	job, err := s.Submit(req)
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Jobs())
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	job, err := s.Job(r.PathValue("id"))
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	job, err := s.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleMethods(w http.ResponseWriter, r *http.Request) {
+	platforms := map[string][]string{}
+	for platform, m := range Methods {
+		for name := range m {
+			platforms[platform] = append(platforms[platform], name)
+		}
+		sort.Strings(platforms[platform])
+	}
+
+	writeJSON(w, http.StatusOK, platforms)
+}
+
+// handleResults streams every crawled item from offset until the job is finished.
+// Items are written as JSONL, or as SSE when client accepts text/event-stream,
+// where event id is item offset and the last "end" event carries job state
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	j, err := s.job(r.PathValue("id"))
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+
+	offset := 0
+	resume := r.URL.Query().Get("offset")
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		resume = lastID
+	}
+	if resume != "" {
+		offset, err = strconv.Atoi(resume)
+		if err != nil || offset < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid offset %s", resume))
+			return
+		}
+		// event id is offset of the last received item
+		if r.Header.Get("Last-Event-ID") != "" {
+			offset = offset + 1
+		}
+	}
+
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		items, done, changed := j.read(offset)
+		for _, item := range items {
+			if sse {
+				fmt.Fprintf(w, "id: %d\ndata: %s\n\n", offset, item)
+			} else {
+				fmt.Fprintf(w, "%s\n", item)
+			}
+			offset = offset + 1
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if done {
+			break
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if sse {
+		content, _ := json.Marshal(j.snapshot())
+		fmt.Fprintf(w, "event: end\ndata: %s\n\n", content)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrServerClosed):
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusBadRequest
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,224 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+	"github.com/nandanurseptama/golang-crawler/tiktok"
+)
+
+var ErrJobCanceled = errors.New("job canceled")
+
+type Status string
+
+const (
+	StatusQueued   Status = "queued"
+	StatusRunning  Status = "running"
+	StatusDone     Status = "done"
+	StatusFailed   Status = "failed"
+	StatusCanceled Status = "canceled"
+)
+
+// Request submits a crawl job
+type Request struct {
+	// One of tiktok or youtube
+	Platform string `json:"platform"`
+	// Crawler method, e.g search or comments
+	Method string `json:"method"`
+	Param  Param  `json:"param"`
+	// Optional, crawl timeout e.g "5m", defaults to Option.Timeout
+	Timeout Duration `json:"timeout"`
+}
+
+// Param of crawler method, see tiktok.SearchParam and youtube.SearchContentParam
+type Param struct {
+	// Search term, user or video id
+	Term string `json:"term"`
+	// Total scroll of page, see tiktok.SearchParam and youtube.SearchContentParam
+	Scroll uint `json:"scroll"`
+	// Stop after this many items, zero means unlimited
+	MaxItems uint `json:"max_items"`
+	// Stop at first item created before this time, user content only
+	Until time.Time `json:"until"`
+	// Stop at this item id, e.g last item seen on previous run
+	StopAtID string `json:"stop_at_id"`
+	// Delay between scroll e.g "3s", youtube only
+	Delay Duration `json:"delay"`
+	// Expand reply thread of every comment, tiktok comments only
+	Replies bool `json:"replies"`
+	// Maximum replies of every thread, zero means unlimited
+	MaxReplies uint `json:"max_replies"`
+	// Search tab, video or photo, tiktok search only
+	Tab tiktok.SearchTab `json:"tab"`
+	// Only content posted within days, tiktok search only
+	PublishedWithin tiktok.PublishTime `json:"published_within"`
+	// Search result order, 0 relevance or 1 likes, tiktok search only
+	SortBy tiktok.SortBy `json:"sort_by"`
+}
+
+// Duration is time.Duration written as string on json, e.g "1m30s"
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		d.Duration = 0
+		return nil
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+
+	return nil
+}
+
+// Job is state of a submitted crawl job
+type Job struct {
+	ID      string  `json:"id"`
+	Request Request `json:"request"`
+	Status  Status  `json:"status"`
+	Error   string  `json:"error,omitempty"`
+	// Kind of failure, e.g captcha or blocked, see crawler errors
+	ErrorKind  string     `json:"error_kind,omitempty"`
+	Items      int        `json:"items"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// job keeps crawled items, so results can be streamed by many clients
+type job struct {
+	cancel context.CancelCauseFunc
+
+	mu    sync.Mutex
+	state Job
+	items []json.RawMessage
+	// closed and replaced every time job changes
+	changed chan struct{}
+}
+
+func newJob(id string, req Request, cancel context.CancelCauseFunc) *job {
+	return &job{
+		cancel: cancel,
+		state: Job{
+			ID:        id,
+			Request:   req,
+			Status:    StatusQueued,
+			CreatedAt: time.Now(),
+		},
+		changed: make(chan struct{}),
+	}
+}
+
+// snapshot returns current state of job
+func (j *job) snapshot() Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.state
+}
+
+// read returns items crawled since offset, whether job is finished
+// and channel closed on the next change
+func (j *job) read(offset int) ([]json.RawMessage, bool, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var items []json.RawMessage
+	if offset < len(j.items) {
+		items = j.items[offset:]
+	}
+
+	return items, finished(j.state.Status), j.changed
+}
+
+// update changes job under lock and wakes up every reader
+func (j *job) update(fn func(state *Job)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	fn(&j.state)
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+func (j *job) start() {
+	j.update(func(state *Job) {
+		now := time.Now()
+		state.Status = StatusRunning
+		state.StartedAt = &now
+	})
+}
+
+func (j *job) add(item json.RawMessage) {
+	j.update(func(state *Job) {
+		j.items = append(j.items, item)
+		state.Items = len(j.items)
+	})
+}
+
+func (j *job) finish(err error, cause error) {
+	j.update(func(state *Job) {
+		now := time.Now()
+		state.FinishedAt = &now
+
+		switch {
+		case errors.Is(cause, ErrJobCanceled):
+			state.Status = StatusCanceled
+			state.Error = ErrJobCanceled.Error()
+		case err != nil:
+			state.Status = StatusFailed
+			state.Error = err.Error()
+			state.ErrorKind = errorKind(err)
+		default:
+			state.Status = StatusDone
+		}
+	})
+}
+
+func finished(status Status) bool {
+	return status == StatusDone || status == StatusFailed || status == StatusCanceled
+}
+
+var errorKinds = map[string]error{
+	"not_found":        crawler.ErrNotFound,
+	"private":          crawler.ErrPrivate,
+	"blocked":          crawler.ErrBlocked,
+	"captcha":          crawler.ErrCaptcha,
+	"schema_changed":   crawler.ErrSchemaChanged,
+	"timeout":          crawler.ErrTimeout,
+	"login_required":   crawler.ErrLoginRequired,
+	"consent_required": crawler.ErrConsentRequired,
+}
+
+func errorKind(err error) string {
+	for kind, target := range errorKinds {
+		if errors.Is(err, target) {
+			return kind
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	return ""
+}
@@ -0,0 +1,219 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+	"github.com/nandanurseptama/golang-crawler/tiktok"
+	"github.com/nandanurseptama/golang-crawler/youtube"
+)
+
+// Method crawls a single platform method, every item is streamed to job result
+type Method struct {
+	// Describes method and its term
+	Usage string
+	// Term can be omitted, e.g category of explore
+	OptionalTerm bool
+	Crawl        func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error]
+}
+
+// Methods of every platform keyed by method name, shared with gocrawl command
+var Methods = map[string]map[string]Method{
+	"tiktok": {
+		"content": {
+			Usage: "get content, term is content url or id",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return single(c.GetContent(ctx, param.Term))
+			}),
+		},
+		"explore": {
+			Usage:        "get trending content of explore page, term is optional category",
+			OptionalTerm: true,
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetExploreStream(ctx, param))
+			}),
+		},
+		"followers": {
+			Usage: "get user followers, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetFollowersStream(ctx, param))
+			}),
+		},
+		"following": {
+			Usage: "get users followed by user, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetFollowingStream(ctx, param))
+			}),
+		},
+		"hashtag": {
+			Usage: "get hashtag content, term is hashtag name",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetHashtagContentStream(ctx, param))
+			}),
+		},
+		"music": {
+			Usage: "get content using music, term is music url or id",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetMusicContentStream(ctx, param))
+			}),
+		},
+		"profile": {
+			Usage: "get user profile, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return single(c.GetUserProfile(ctx, param.Term))
+			}),
+		},
+		"related": {
+			Usage: "get content related to a video, term is content url or id",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetRelatedContentStream(ctx, param))
+			}),
+		},
+		"search": {
+			Usage: "search content, term is search keyword",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.SearchStream(ctx, param))
+			}),
+		},
+		"search-live": {
+			Usage: "search live rooms, term is search keyword",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.SearchLiveStream(ctx, param))
+			}),
+		},
+		"search-user": {
+			Usage: "search user, term is search keyword",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.SearchUserStream(ctx, param))
+			}),
+		},
+		"comments": {
+			Usage: "get content comments, term is content url or id",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetContentCommentsStream(ctx, param))
+			}),
+		},
+		"live": {
+			Usage: "get live room of user, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return single(c.GetUserLive(ctx, param.Term))
+			}),
+		},
+		"user-content": {
+			Usage: "get user content, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetUserContentStream(ctx, param))
+			}),
+		},
+		"user-reposts": {
+			Usage: "get content reposted by user, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetUserRepostsStream(ctx, param))
+			}),
+		},
+		"user-liked": {
+			Usage: "get content liked by user, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetUserLikedStream(ctx, param))
+			}),
+		},
+		"user-collections": {
+			Usage: "get content saved to favorites by user, term is username",
+			Crawl: tiktokMethod(func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error] {
+				return stream(c.GetUserCollectionsStream(ctx, param))
+			}),
+		},
+	},
+	"youtube": {
+		"search": {
+			Usage: "search video, term is search keyword",
+			Crawl: youtubeMethod(func(c youtube.YoutubeCrawler, ctx context.Context, param youtube.SearchContentParam) iter.Seq2[any, error] {
+				return stream(c.SearchContentStream(ctx, param))
+			}),
+		},
+		"channels": {
+			Usage: "search channel, term is search keyword",
+			Crawl: youtubeMethod(func(c youtube.YoutubeCrawler, ctx context.Context, param youtube.SearchContentParam) iter.Seq2[any, error] {
+				return stream(c.SearchChannelStream(ctx, param))
+			}),
+		},
+		"videos": {
+			Usage: "get channel videos, term is channel handle",
+			Crawl: youtubeMethod(func(c youtube.YoutubeCrawler, ctx context.Context, param youtube.SearchContentParam) iter.Seq2[any, error] {
+				return stream(c.GetUserContentStream(ctx, param))
+			}),
+		},
+		"comments": {
+			Usage: "get video comments, term is video id",
+			Crawl: youtubeMethod(func(c youtube.YoutubeCrawler, ctx context.Context, param youtube.SearchContentParam) iter.Seq2[any, error] {
+				return stream(c.GetContentCommentsStream(ctx, param))
+			}),
+		},
+	},
+}
+
+func tiktokMethod(
+	call func(c tiktok.TiktokCrawler, ctx context.Context, param tiktok.SearchParam) iter.Seq2[any, error],
+) func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+	return func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+		return call(tiktok.NewCrawler(config), ctx, tiktok.SearchParam{
+			Term:            param.Term,
+			Scroll:          param.Scroll,
+			MaxItems:        param.MaxItems,
+			Until:           param.Until,
+			StopAtID:        param.StopAtID,
+			Replies:         param.Replies,
+			MaxReplies:      param.MaxReplies,
+			Tab:             param.Tab,
+			PublishedWithin: param.PublishedWithin,
+			SortBy:          param.SortBy,
+		})
+	}
+}
+
+func youtubeMethod(
+	call func(c youtube.YoutubeCrawler, ctx context.Context, param youtube.SearchContentParam) iter.Seq2[any, error],
+) func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+	return func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+		delay := param.Delay.Duration
+		if delay <= 0 {
+			delay = 3 * time.Second
+		}
+
+		return call(youtube.NewCrawler(config), ctx, youtube.SearchContentParam{
+			Term:                param.Term,
+			Scroll:              param.Scroll,
+			DelayScrollDuration: delay,
+			MaxItems:            param.MaxItems,
+			Until:               param.Until,
+			StopAtID:            param.StopAtID,
+		})
+	}
+}
+
+// stream converts typed crawl stream into job stream
+func stream[T any](seq iter.Seq2[T, error]) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for item, err := range seq {
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// single converts single item result into job stream
+func single[T any](item T, err error) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(item, nil)
+	}
+}
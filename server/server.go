@@ -0,0 +1,272 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+// Package server exposes tiktok and youtube crawlers as jobs over HTTP/JSON.
+//
+//	POST   /jobs              submit a job, body is Request
+//	GET    /jobs              list jobs
+//	GET    /jobs/{id}         job status
+//	GET    /jobs/{id}/results stream results as JSONL, or SSE when accepting text/event-stream
+//	DELETE /jobs/{id}         cancel a job, or remove it once finished
+//	GET    /methods           list crawler methods of every platform
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+var (
+	ErrJobNotFound   = errors.New("job not found")
+	ErrServerClosed  = errors.New("server is closed")
+	ErrInvalidMethod = errors.New("invalid crawler method")
+)
+
+type Option struct {
+	// Maximum jobs crawling at the same time, the rest are queued
+	MaxConcurrent int
+	// Default crawl timeout of a job, zero means no timeout
+	Timeout time.Duration
+	// Finished job is removed after this duration, zero means it is kept until deleted
+	Retention time.Duration
+}
+
+// Server runs crawl jobs on one shared crawler config
+type Server struct {
+	config crawler.Config
+	option Option
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	// limit running jobs
+	slots chan struct{}
+	mux   *http.ServeMux
+
+	mu     sync.Mutex
+	closed bool
+	jobs   map[string]*job
+	// job ids in submitted order
+	order []string
+}
+
+func New(config crawler.Config, option Option) *Server {
+	if option.MaxConcurrent < 1 {
+		option.MaxConcurrent = 1
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	s := &Server{
+		config: config,
+		option: option,
+		ctx:    ctx,
+		cancel: cancel,
+		slots:  make(chan struct{}, option.MaxConcurrent),
+		jobs:   map[string]*job{},
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /jobs", s.handleSubmit)
+	s.mux.HandleFunc("GET /jobs", s.handleList)
+	s.mux.HandleFunc("GET /jobs/{id}", s.handleGet)
+	s.mux.HandleFunc("GET /jobs/{id}/results", s.handleResults)
+	s.mux.HandleFunc("DELETE /jobs/{id}", s.handleDelete)
+	s.mux.HandleFunc("GET /methods", s.handleMethods)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Submit queues crawl job of request
+func (s *Server) Submit(req Request) (Job, error) {
+	m, ok := Methods[req.Platform][req.Method]
+	if !ok {
+		return Job{}, fmt.Errorf("%w : %s %s", ErrInvalidMethod, req.Platform, req.Method)
+	}
+	if req.Param.Term == "" && !m.OptionalTerm {
+		return Job{}, errors.New("param term is required")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return Job{}, ErrServerClosed
+	}
+
+	ctx, cancel := context.WithCancelCause(s.ctx)
+	j := newJob(id, req, cancel)
+	s.jobs[id] = j
+	s.order = append(s.order, id)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, j, m)
+	}()
+
+	return j.snapshot(), nil
+}
+
+// Job returns state of job id
+func (s *Server) Job(id string) (Job, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return j.snapshot(), nil
+}
+
+// Jobs returns state of every job in submitted order
+func (s *Server) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.order))
+	for _, id := range s.order {
+		jobs = append(jobs, s.jobs[id].snapshot())
+	}
+
+	return jobs
+}
+
+// Cancel cancels queued or running job through its context.
+// Finished job is removed instead
+func (s *Server) Cancel(id string) (Job, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	state := j.snapshot()
+	if finished(state.Status) {
+		s.remove(id)
+		return state, nil
+	}
+
+	j.cancel(ErrJobCanceled)
+
+	return j.snapshot(), nil
+}
+
+// Close cancels every job and waits them finished
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel(ErrJobCanceled)
+	s.wg.Wait()
+}
+
+func (s *Server) run(ctx context.Context, j *job, m Method) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		j.finish(nil, context.Cause(ctx))
+		s.expire(j)
+		return
+	}
+	defer func() { <-s.slots }()
+
+	j.start()
+
+	req := j.snapshot().Request
+	timeout := s.option.Timeout
+	if req.Timeout.Duration > 0 {
+		timeout = req.Timeout.Duration
+	}
+	crawlCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		crawlCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var crawlErr error
+	for item, err := range m.Crawl(crawlCtx, s.config, req.Param) {
+		if err != nil {
+			crawlErr = err
+			break
+		}
+
+		content, err := json.Marshal(item)
+		if err != nil {
+			crawlErr = err
+			break
+		}
+		j.add(content)
+	}
+
+	j.finish(crawlErr, context.Cause(ctx))
+	// release job context
+	j.cancel(context.Canceled)
+	s.expire(j)
+}
+
+// expire removes finished job after retention
+func (s *Server) expire(j *job) {
+	if s.option.Retention <= 0 {
+		return
+	}
+
+	id := j.snapshot().ID
+	time.AfterFunc(s.option.Retention, func() {
+		s.remove(id)
+	})
+}
+
+func (s *Server) job(id string) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	return j, nil
+}
+
+func (s *Server) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[id]; !ok {
+		return
+	}
+	delete(s.jobs, id)
+	for i, v := range s.order {
+		if v == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job id : %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,481 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+type stubItem struct {
+	N int `json:"n"`
+}
+
+// stubMethods registers test platform methods for the duration of the test:
+//   - items yields param.MaxItems items
+//   - blocked blocks until job is canceled
+//   - captcha yields param.MaxItems items then fails of captcha
+func stubMethods(t *testing.T) {
+	items := func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+		return func(yield func(any, error) bool) {
+			for i := range int(param.MaxItems) {
+				if !yield(stubItem{N: i}, nil) {
+					return
+				}
+			}
+		}
+	}
+
+	Methods["test"] = map[string]Method{
+		"items": {Crawl: items},
+		"blocked": {Crawl: func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+			return func(yield func(any, error) bool) {
+				<-ctx.Done()
+				yield(nil, ctx.Err())
+			}
+		}},
+		"captcha": {Crawl: func(ctx context.Context, config crawler.Config, param Param) iter.Seq2[any, error] {
+			return func(yield func(any, error) bool) {
+				for item, err := range items(ctx, config, param) {
+					if !yield(item, err) {
+						return
+					}
+				}
+				yield(nil, fmt.Errorf("stub err : %w", crawler.ErrCaptcha))
+			}
+		}},
+	}
+	t.Cleanup(func() { delete(Methods, "test") })
+}
+
+func newTestServer(t *testing.T, option Option) (*Server, *httptest.Server) {
+	stubMethods(t)
+	s := New(crawler.Config{}, option)
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+
+	return s, ts
+}
+
+func submit(t *testing.T, ts *httptest.Server, req Request) Job {
+	body, _ := json.Marshal(req)
+	resp, err := http.Post(ts.URL+"/jobs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		content, _ := io.ReadAll(resp.Body)
+		t.Fatalf("submit status %d : %s", resp.StatusCode, content)
+	}
+	if resp.Header.Get("Location") == "" {
+		t.Error("submit has no location")
+	}
+
+	var job Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+
+	return job
+}
+
+func getJob(t *testing.T, ts *httptest.Server, id string) (Job, int) {
+	resp, err := http.Get(ts.URL + "/jobs/" + id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var job Job
+	json.NewDecoder(resp.Body).Decode(&job)
+
+	return job, resp.StatusCode
+}
+
+// waitJob waits until job is finished
+func waitJob(t *testing.T, s *Server, id string) Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := s.Job(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if finished(job.Status) {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s is not finished", id)
+	return Job{}
+}
+
+func TestSubmitInvalid(t *testing.T) {
+	_, ts := newTestServer(t, Option{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "unknown method", body: `{"platform":"test","method":"unknown","param":{"term":"x"}}`},
+		{name: "unknown platform", body: `{"platform":"unknown","method":"items","param":{"term":"x"}}`},
+		{name: "missing term", body: `{"platform":"test","method":"items"}`},
+		{name: "invalid json", body: `{`},
+		{name: "invalid timeout", body: `{"platform":"test","method":"items","param":{"term":"x"},"timeout":"soon"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/jobs", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestSubmitAndCancel(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "blocked", Param: Param{Term: "x"}})
+	if job.ID == "" || finished(job.Status) {
+		t.Fatalf("got job %+v, want queued or running job", job)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/jobs/"+job.ID, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("cancel status %d", resp.StatusCode)
+	}
+
+	job = waitJob(t, s, job.ID)
+	if job.Status != StatusCanceled || job.Error != ErrJobCanceled.Error() || job.ErrorKind != "" {
+		t.Errorf("got job %+v, want canceled", job)
+	}
+
+	// finished job is removed on the second cancel
+	if _, err := s.Cancel(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, code := getJob(t, ts, job.ID); code != http.StatusNotFound {
+		t.Errorf("got status %d of removed job, want %d", code, http.StatusNotFound)
+	}
+	if _, err := s.Cancel(job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("got err %v, want %v", err, ErrJobNotFound)
+	}
+}
+
+func TestQueuedJobCanceled(t *testing.T) {
+	s, ts := newTestServer(t, Option{MaxConcurrent: 1})
+
+	running := submit(t, ts, Request{Platform: "test", Method: "blocked", Param: Param{Term: "x"}})
+	// the only slot is taken before the next job is submitted
+	for job, _ := s.Job(running.ID); job.Status != StatusRunning; job, _ = s.Job(running.ID) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	queued := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "x", MaxItems: 1}})
+
+	if _, err := s.Cancel(queued.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job := waitJob(t, s, queued.ID); job.Status != StatusCanceled || job.StartedAt != nil {
+		t.Errorf("got job %+v, want canceled before started", job)
+	}
+
+	if job, _ := s.Job(running.ID); job.Status != StatusRunning {
+		t.Errorf("got status %s of running job", job.Status)
+	}
+}
+
+func TestJobStatus(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	tests := []struct {
+		method    string
+		status    Status
+		errorKind string
+	}{
+		{method: "items", status: StatusDone},
+		{method: "captcha", status: StatusFailed, errorKind: "captcha"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			job := submit(t, ts, Request{Platform: "test", Method: tt.method, Param: Param{Term: "x", MaxItems: 2}})
+			waitJob(t, s, job.ID)
+
+			job, code := getJob(t, ts, job.ID)
+			if code != http.StatusOK {
+				t.Fatalf("got status %d", code)
+			}
+			if job.Status != tt.status || job.ErrorKind != tt.errorKind || job.Items != 2 {
+				t.Errorf("got job %+v, want status %s, error kind %q and 2 items", job, tt.status, tt.errorKind)
+			}
+			if job.StartedAt == nil || job.FinishedAt == nil {
+				t.Errorf("got job %+v, want started and finished time", job)
+			}
+			if tt.errorKind != "" && !strings.Contains(job.Error, crawler.ErrCaptcha.Error()) {
+				t.Errorf("got error %q", job.Error)
+			}
+		})
+	}
+}
+
+func TestJobTimeout(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "blocked", Param: Param{Term: "x"}, Timeout: Duration{50 * time.Millisecond}})
+	job = waitJob(t, s, job.ID)
+	if job.Status != StatusFailed || job.ErrorKind != "timeout" {
+		t.Errorf("got job %+v, want failed of timeout", job)
+	}
+}
+
+func TestResults(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "x", MaxItems: 3}})
+	waitJob(t, s, job.ID)
+
+	tests := []struct {
+		name   string
+		query  string
+		header map[string]string
+		want   []int
+	}{
+		{name: "all", want: []int{0, 1, 2}},
+		{name: "offset", query: "?offset=1", want: []int{1, 2}},
+		{name: "offset past end", query: "?offset=5"},
+		{name: "last event id", header: map[string]string{"Last-Event-ID": "0"}, want: []int{1, 2}},
+		// Last-Event-ID of reconnecting client wins over offset
+		{name: "last event id over offset", query: "?offset=0", header: map[string]string{"Last-Event-ID": "1"}, want: []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, ts.URL+"/jobs/"+job.ID+"/results"+tt.query, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Errorf("got content type %s", ct)
+			}
+
+			var got []int
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var item stubItem
+				if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, item.N)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got items %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultsInvalidOffset(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "x"}})
+	waitJob(t, s, job.ID)
+
+	for _, query := range []string{"?offset=-1", "?offset=x"} {
+		resp, err := http.Get(ts.URL + "/jobs/" + job.ID + "/results" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", query, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestResultsSSE(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "captcha", Param: Param{Term: "x", MaxItems: 3}})
+	waitJob(t, s, job.ID)
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/jobs/"+job.ID+"/results", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	content, _ := io.ReadAll(resp.Body)
+	events := strings.Split(strings.TrimSpace(string(content)), "\n\n")
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3 : %s", len(events), content)
+	}
+	for i, want := range []string{"id: 1\ndata: {\"n\":1}", "id: 2\ndata: {\"n\":2}"} {
+		if events[i] != want {
+			t.Errorf("event %d: got %q, want %q", i, events[i], want)
+		}
+	}
+
+	end, ok := strings.CutPrefix(events[2], "event: end\ndata: ")
+	if !ok {
+		t.Fatalf("got last event %q, want end event", events[2])
+	}
+	var state Job
+	if err := json.Unmarshal([]byte(end), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != StatusFailed || state.ErrorKind != "captcha" || state.Items != 3 {
+		t.Errorf("got end state %+v", state)
+	}
+}
+
+func TestResultsFollowRunningJob(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "blocked", Param: Param{Term: "x"}})
+
+	done := make(chan []byte)
+	go func() {
+		resp, err := http.Get(ts.URL + "/jobs/" + job.ID + "/results")
+		if err != nil {
+			done <- nil
+			return
+		}
+		defer resp.Body.Close()
+		content, _ := io.ReadAll(resp.Body)
+		done <- content
+	}()
+
+	// stream is kept open until job is finished
+	select {
+	case <-done:
+		t.Fatal("stream of running job is finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	s.Cancel(job.ID)
+	select {
+	case content := <-done:
+		if len(content) > 0 {
+			t.Errorf("got %q, want no items", content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream is not finished after job canceled")
+	}
+}
+
+func TestRetention(t *testing.T) {
+	s, ts := newTestServer(t, Option{Retention: 50 * time.Millisecond})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "x", MaxItems: 1}})
+	waitJob(t, s, job.ID)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := s.Job(job.ID); errors.Is(err, ErrJobNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("finished job is not removed after retention")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if jobs := s.Jobs(); len(jobs) != 0 {
+		t.Errorf("got %d jobs, want none", len(jobs))
+	}
+}
+
+func TestNoRetention(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	job := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "x"}})
+	waitJob(t, s, job.ID)
+	time.Sleep(50 * time.Millisecond)
+
+	if _, err := s.Job(job.ID); err != nil {
+		t.Errorf("finished job without retention is removed : %v", err)
+	}
+}
+
+func TestSubmitAfterClose(t *testing.T) {
+	s, _ := newTestServer(t, Option{})
+	s.Close()
+
+	if _, err := s.Submit(Request{Platform: "test", Method: "items", Param: Param{Term: "x"}}); !errors.Is(err, ErrServerClosed) {
+		t.Errorf("got err %v, want %v", err, ErrServerClosed)
+	}
+}
+
+func TestListJobsAndMethods(t *testing.T) {
+	s, ts := newTestServer(t, Option{})
+
+	first := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "a"}})
+	second := submit(t, ts, Request{Platform: "test", Method: "items", Param: Param{Term: "b"}})
+	waitJob(t, s, first.ID)
+	waitJob(t, s, second.ID)
+
+	resp, err := http.Get(ts.URL + "/jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var jobs []Job
+	json.NewDecoder(resp.Body).Decode(&jobs)
+	if len(jobs) != 2 || jobs[0].ID != first.ID || jobs[1].ID != second.ID {
+		t.Errorf("got jobs %+v, want jobs in submitted order", jobs)
+	}
+
+	resp, err = http.Get(ts.URL + "/methods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var platforms map[string][]string
+	json.NewDecoder(resp.Body).Decode(&platforms)
+	if got := fmt.Sprint(platforms["test"]); got != "[blocked captcha items]" {
+		t.Errorf("got test methods %s", got)
+	}
+	if len(platforms["tiktok"]) == 0 || len(platforms["youtube"]) == 0 {
+		t.Errorf("got methods %v, want tiktok and youtube methods", platforms)
+	}
+}
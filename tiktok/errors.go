@@ -0,0 +1,112 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// known status code of tiktok api
+var statusErrors = map[int]error{
+	10000: crawler.ErrCaptcha,
+	10101: crawler.ErrLoginRequired,
+	10201: crawler.ErrNotFound,
+	10202: crawler.ErrNotFound,
+	10204: crawler.ErrNotFound,
+	10216: crawler.ErrPrivate,
+	10221: crawler.ErrBlocked,
+	10222: crawler.ErrPrivate,
+}
+
+// statusError returns error of non zero api status code
+func statusError(statusCode int) error {
+	if statusCode == 0 {
+		return nil
+	}
+
+	return &crawler.Error{
+		Platform:   "tiktok",
+		StatusCode: statusCode,
+		Kind:       statusErrors[statusCode],
+	}
+}
+
+// pageStates maps selector shown by tiktok page to its error
+var pageStates = []struct {
+	selector string
+	kind     error
+}{
+	{`#captcha-verify-container-main-page, .captcha-verify-container, #tiktok-verify-ele`, crawler.ErrCaptcha},
+	{`[data-e2e="login-modal"]`, crawler.ErrLoginRequired},
+	{`[data-e2e="user-page-private"], [data-e2e="private-account"]`, crawler.ErrPrivate},
+	{`[data-e2e="user-page-not-found"], [data-e2e="video-unavailable"]`, crawler.ErrNotFound},
+}
+
+// checkPage reports error when tiktok page shows captcha, login or unavailable state
+func checkPage(ctx context.Context) error {
+	return checkPageStates(ctx, crawler.ErrCaptcha, crawler.ErrLoginRequired, crawler.ErrPrivate, crawler.ErrNotFound)
+}
+
+// checkCaptcha reports error when tiktok page shows captcha,
+// e.g on page whose data is still readable when private
+func checkCaptcha(ctx context.Context) error {
+	return checkPageStates(ctx, crawler.ErrCaptcha)
+}
+
+// checkPageStates reports error when tiktok page shows state of one of kinds
+func checkPageStates(ctx context.Context, kinds ...error) error {
+	for _, state := range pageStates {
+		if !slices.Contains(kinds, state.kind) {
+			continue
+		}
+
+		var shown bool
+		err := chromedp.Evaluate(`document.querySelector('`+state.selector+`') !== null`, &shown).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		if shown {
+			return crawler.NewError("tiktok", "", state.kind, nil)
+		}
+	}
+
+	return nil
+}
+
+// waitShown waits selector shown on page, e.g list opened by a click.
+// Selector not shown before timeout reports private list of api pattern
+func waitShown(ctx context.Context, selector string, timeout time.Duration, pattern string) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return crawler.NewError("tiktok", pattern, crawler.ErrPrivate, nil)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if err := checkPageStates(ctx, crawler.ErrCaptcha, crawler.ErrLoginRequired); err != nil {
+			return err
+		}
+
+		var shown bool
+		err := chromedp.Evaluate(`document.querySelector('`+selector+`') !== null`, &shown).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if shown {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+func TestStatusError(t *testing.T) {
+	if err := statusError(0); err != nil {
+		t.Fatalf("statusError(0) = %v", err)
+	}
+
+	tests := map[int]error{
+		10000: crawler.ErrCaptcha,
+		10101: crawler.ErrLoginRequired,
+		10201: crawler.ErrNotFound,
+		10216: crawler.ErrPrivate,
+		10221: crawler.ErrBlocked,
+	}
+	for code, kind := range tests {
+		if err := statusError(code); !errors.Is(err, kind) {
+			t.Errorf("statusError(%d) = %v, want %v", code, err, kind)
+		}
+	}
+
+	// unknown status code is still an error, of no kind
+	var crawlErr *crawler.Error
+	if err := statusError(1); !errors.As(err, &crawlErr) || crawlErr.Kind != nil || crawlErr.StatusCode != 1 {
+		t.Errorf("statusError(1) = %v", err)
+	}
+}
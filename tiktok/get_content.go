@@ -0,0 +1,22 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+)
+
+// GetContent returns content of content url or id, either video or photo post
+func (crawler *Tiktok) GetContent(ctx context.Context, contentURLOrID string) (ContentItemResp, error) {
+	return newDocument(crawler.config, contentURL(contentURLOrID), "webapp.video-detail", decodeContent).Fetch(ctx)
+}
+
+func decodeContent(body []byte) (ContentItemResp, error) {
+	var detail VideoDetailResp
+	if err := decodeHydration(body, &detail); err != nil {
+		return ContentItemResp{}, err
+	}
+
+	return detail.ItemInfo.ItemStruct, nil
+}
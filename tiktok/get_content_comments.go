@@ -0,0 +1,107 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+func (crawler *Tiktok) GetContentComments(ctx context.Context, param SearchParam) ([]CommentItem, error) {
+	return crawler.contentCommentsPaginator(param).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetContentCommentsStream(ctx context.Context, param SearchParam) iter.Seq2[CommentItem, error] {
+	return crawler.contentCommentsPaginator(param).Seq(ctx)
+}
+
+func (t *Tiktok) contentCommentsPaginator(param SearchParam) *crawler.Paginator[CommentItem] {
+	paginator := newPaginator(t.config, contentURL(param.Term), "*/api/comment/list/*", param, decodeContentComments)
+	// first comment never shows on content without comments,
+	// so the page is ready with its description and the list comes from the first page
+	paginator.ReadySelector = `[data-e2e="browse-video-desc"], [data-e2e="video-desc"]`
+	// comments disabled content asks for no comment list
+	paginator.PageTimeout = 15 * time.Second
+	// comment list is scrolled instead of the page
+	paginator.ScrollScript = `(() => {
+	const comments = document.querySelectorAll('[data-e2e="comment-level-1"]');
+	if (comments.length > 0) comments[comments.length - 1].scrollIntoView();
+	else window.scrollTo(0, document.body.scrollHeight);
+})()`
+	paginator.Stop = stopCondition(param, func(item CommentItem) string {
+		return item.Id
+	}, nil)
+
+	if param.Replies {
+		paginator.Expand = &crawler.Expansion[CommentItem]{
+			Pattern: "*/api/comment/list/reply/*",
+			Decode:  decodeContentComments,
+			Has: func(item CommentItem) bool {
+				return item.ParentId == "" && item.ReplyCount > 0
+			},
+			Load:     loadReplies,
+			MaxItems: param.MaxReplies,
+		}
+	}
+
+	return paginator
+}
+
+// loadReplies clicks "view replies" control of index-th comment,
+// the control turns into "view more" until the thread is fully loaded
+func loadReplies(ctx context.Context, index int, item CommentItem) (bool, error) {
+	var clicked bool
+	err := chromedp.Evaluate(fmt.Sprintf(`((index) => {
+	const comment = document.querySelectorAll('[data-e2e="comment-level-1"]')[index];
+	const thread = comment && comment.closest('[class*="DivCommentItemContainer"], [class*="DivCommentObjectWrapper"]');
+	if (!thread) return false;
+	const controls = thread.querySelectorAll('[data-e2e^="view-more"], [class*="DivViewRepliesContainer"] > :first-child');
+	const control = controls[controls.length - 1];
+	if (!control) return false;
+	control.scrollIntoView({block: "center"});
+	control.click();
+	return true;
+})(%d)`, index), &clicked).Do(ctx)
+
+	return clicked, err
+}
+
+// contentURL returns video page of content url or id
+func contentURL(term string) string {
+	if strings.HasPrefix(term, "http://") || strings.HasPrefix(term, "https://") {
+		return term
+	}
+
+	// tiktok redirects to the author of the video
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/@/video/" + term}
+	return uri.String()
+}
+
+func decodeContentComments(body []byte) ([]CommentItem, bool, error) {
+	var commentsResp GetCommentsResp
+	err := json.Unmarshal(body, &commentsResp)
+	if err != nil {
+		return []CommentItem{}, false, err
+	}
+	if err := statusError(commentsResp.StatusCode); err != nil {
+		return []CommentItem{}, false, err
+	}
+
+	for i, v := range commentsResp.Comments {
+		// top level comment replies to "0"
+		if v.ParentId == "0" {
+			commentsResp.Comments[i].ParentId = ""
+		}
+	}
+
+	return commentsResp.Comments, commentsResp.HasMore == 1, nil
+}
@@ -0,0 +1,31 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeContentComments(t *testing.T) {
+	comments, hasMore, err := decodeContentComments([]byte(`{"status_code":0,"has_more":1,"cursor":20,"total":2,"comments":[
+		{"cid":"7310000000000000001","aweme_id":"7300000000000000001","reply_id":"0","text":"so cute","reply_comment_total":1,"user":{"uid":"1","unique_id":"a"}},
+		{"cid":"7310000000000000002","aweme_id":"7300000000000000001","reply_id":"7310000000000000001","text":"agreed","user":{"uid":"2","unique_id":"b"}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	if len(comments) != 2 {
+		t.Fatalf("comments = %+v", comments)
+	}
+	// top level comment has no parent
+	if comments[0].ParentId != "" || comments[1].ParentId != "7310000000000000001" {
+		t.Errorf("parent ids = %q %q", comments[0].ParentId, comments[1].ParentId)
+	}
+	if comments[0].ReplyCount != 1 || comments[1].User.UniqueId != "b" {
+		t.Errorf("comments = %+v", comments)
+	}
+}
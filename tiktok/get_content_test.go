@@ -0,0 +1,44 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeContent(t *testing.T) {
+	content, err := decodeContent([]byte(`{"statusCode":0,"itemInfo":{"itemStruct":{
+		"id":"7300000000000000002","desc":"morning kitty",
+		"imagePost":{"title":"morning kitty","images":[
+			{"imageURL":{"urlList":["https://p16-sign.tiktokcdn.com/1.jpeg"]},"imageWidth":1080,"imageHeight":1440},
+			{"imageURL":{"urlList":["https://p16-sign.tiktokcdn.com/2.jpeg"]},"imageWidth":1080,"imageHeight":1440}
+		]}
+	}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if content.Id != "7300000000000000002" || content.ContentType != ContentTypePhoto {
+		t.Fatalf("content = %+v", content)
+	}
+	for i, image := range content.ImagePost.Images {
+		if image.Index != i {
+			t.Errorf("image %d has index %d", i, image.Index)
+		}
+	}
+	if len(content.RawJSON) == 0 {
+		t.Error("raw json is empty")
+	}
+}
+
+func TestDecodeContentVideo(t *testing.T) {
+	content, err := decodeContent([]byte(`{"statusCode":0,"itemInfo":{"itemStruct":{"id":"7300000000000000001","video":{}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if content.ContentType != ContentTypeVideo || content.ImagePost != nil {
+		t.Fatalf("content = %+v", content)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// GetExplore returns trending content of explore page, bounded by Scroll and MaxItems.
+// Term is optional category chip, e.g "Comedy", empty term keeps the default feed
+func (crawler *Tiktok) GetExplore(ctx context.Context, param SearchParam) ([]ExploreItemResp, error) {
+	return crawler.explorePaginator(param).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetExploreStream(ctx context.Context, param SearchParam) iter.Seq2[ExploreItemResp, error] {
+	return crawler.explorePaginator(param).Seq(ctx)
+}
+
+func (t *Tiktok) explorePaginator(param SearchParam) *crawler.Paginator[ExploreItemResp] {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/explore"}
+	category := param.Term
+	// default feed loaded before the chip is clicked is dropped by Reload,
+	// so every decoded page belongs to category
+	decode := func(body []byte) ([]ExploreItemResp, bool, error) {
+		items, hasMore, err := decodeItemList(body)
+		if err != nil {
+			return []ExploreItemResp{}, false, err
+		}
+
+		var exploreItems []ExploreItemResp
+		for _, item := range items {
+			exploreItems = append(exploreItems, ExploreItemResp{Category: category, Content: item})
+		}
+
+		return exploreItems, hasMore, nil
+	}
+
+	paginator := newPaginator(t.config, uri.String(), "*/api/explore/item_list/*", param, decode)
+	paginator.ReadySelector = `[data-e2e="explore-item-list"]`
+	paginator.Stop = stopCondition(param, func(item ExploreItemResp) string {
+		return item.Content.Id
+	}, nil)
+
+	if category != "" {
+		paginator.Actions = append(paginator.Actions, selectExploreCategory(category))
+		paginator.Reload = true
+	}
+
+	return paginator
+}
+
+// selectExploreCategory clicks category chip whose label is category, ignoring case
+func selectExploreCategory(category string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		label, err := json.Marshal(category)
+		if err != nil {
+			return err
+		}
+
+		var clicked bool
+		err = chromedp.Evaluate(fmt.Sprintf(`((label) => {
+	const chip = [...document.querySelectorAll('[data-e2e="explore-category-chip"], [role="tab"], button')]
+		.find(e => e.textContent.trim().toLowerCase() === label.toLowerCase());
+	if (!chip) return false;
+	chip.click();
+	return true;
+})(%s)`, label), &clicked).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if !clicked {
+			return crawler.NewError("tiktok", "explore category", crawler.ErrNotFound, errors.New("category "+category+" is not shown"))
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/url"
+	"strings"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// GetHashtagContent returns challenge of hashtag term and its content.
+// Content crawled before failure is returned together with the error
+func (crawler *Tiktok) GetHashtagContent(ctx context.Context, param SearchParam) (HashtagContent, error) {
+	var result HashtagContent
+	items, err := crawler.hashtagContentPaginator(param, &result.Challenge).Collect(ctx)
+	result.Items = items
+
+	return result, err
+}
+
+// GetHashtagContentStream streams hashtag content, every item carries the challenge
+func (crawler *Tiktok) GetHashtagContentStream(ctx context.Context, param SearchParam) iter.Seq2[HashtagItemResp, error] {
+	return func(yield func(HashtagItemResp, error) bool) {
+		// filled once the page is ready, before the first item
+		var challenge ChallengeResp
+		for item, err := range crawler.hashtagContentPaginator(param, &challenge).Seq(ctx) {
+			if !yield(HashtagItemResp{Challenge: challenge, Content: item}, err) {
+				return
+			}
+		}
+	}
+}
+
+// hashtagContentPaginator returns paginator of hashtag content,
+// challenge is filled once the page is ready
+func (t *Tiktok) hashtagContentPaginator(param SearchParam, challenge *ChallengeResp) *crawler.Paginator[ContentItemResp] {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/tag/" + strings.TrimPrefix(param.Term, "#")}
+
+	paginator := newPaginator(t.config, uri.String(), "*/api/challenge/item_list/*", param, decodeItemList)
+	paginator.ReadySelector = `[data-e2e="challenge-item-list"]`
+	// Stop sees item together with its challenge, as it is streamed
+	stop := stopCondition(param, func(item HashtagItemResp) string {
+		return item.Content.Id
+	}, nil)
+	paginator.Stop = func(item ContentItemResp) bool {
+		return stop(HashtagItemResp{Challenge: *challenge, Content: item})
+	}
+
+	script := hydrationScript("webapp.challenge-detail")
+	paginator.Actions = append(paginator.Actions, crawler.EvaluateJSON(t.config.Cassette, "challenge", script, func(body []byte) error {
+		return decodeChallenge(body, challenge)
+	}))
+
+	return paginator
+}
+
+func decodeChallenge(body []byte, challenge *ChallengeResp) error {
+	var detail ChallengeDetailResp
+	if err := decodeHydration(body, &detail); err != nil {
+		return err
+	}
+
+	*challenge = detail.ChallengeInfo.Challenge
+	challenge.Stats = detail.ChallengeInfo.Stats
+
+	return nil
+}
+
+func decodeItemList(body []byte) ([]ContentItemResp, bool, error) {
+	var itemList ItemListResp
+	err := json.Unmarshal(body, &itemList)
+	if err != nil {
+		return []ContentItemResp{}, false, err
+	}
+	if err := statusError(itemList.StatusCode); err != nil {
+		return []ContentItemResp{}, false, err
+	}
+
+	return itemList.ItemList, itemList.HasMore, nil
+}
@@ -0,0 +1,78 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+func TestDecodeItemList(t *testing.T) {
+	items, hasMore, err := decodeItemList([]byte(`{"statusCode":0,"itemList":[{"id":"1"},{"id":"2"}],"hasMore":true,"cursor":"30"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	if ids := contentIDs(items); !slices.Equal(ids, []string{"1", "2"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+
+	_, _, err = decodeItemList([]byte(`{"statusCode":10201}`))
+	if !errors.Is(err, crawler.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDecodeChallenge(t *testing.T) {
+	var challenge ChallengeResp
+	err := decodeChallenge([]byte(`{"statusCode":0,"challengeInfo":{
+		"challenge":{"id":"3700","title":"cat","desc":"all about cats"},
+		"stats":{"videoCount":1000,"viewCount":200000}
+	}}`), &challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if challenge.Id != "3700" || challenge.Title != "cat" || challenge.Stats.VideoCount != 1000 || challenge.Stats.ViewCount != 200000 {
+		t.Fatalf("challenge = %+v", challenge)
+	}
+
+	err = decodeChallenge([]byte(`{"statusCode":10204}`), &challenge)
+	if !errors.Is(err, crawler.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestHashtagContentStop(t *testing.T) {
+	var challenge ChallengeResp
+	param := SearchParam{
+		StopAtID: "3",
+		Stop: func(item any) bool {
+			v := item.(HashtagItemResp)
+			return v.Challenge.Title == "cat" && v.Content.Desc == "ad"
+		},
+	}
+	paginator := (&Tiktok{}).hashtagContentPaginator(param, &challenge)
+	// filled by the page actions before the first item
+	challenge.Title = "cat"
+
+	tests := []struct {
+		item ContentItemResp
+		want bool
+	}{
+		{ContentItemResp{Id: "1"}, false},
+		{ContentItemResp{Id: "2", Desc: "ad"}, true},
+		{ContentItemResp{Id: "3"}, true},
+	}
+	for _, tt := range tests {
+		if got := paginator.Stop(tt.item); got != tt.want {
+			t.Errorf("Stop(%+v) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
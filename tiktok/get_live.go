@@ -0,0 +1,146 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// SearchLive returns live rooms of search LIVE tab, bounded by Scroll and MaxItems
+func (crawler *Tiktok) SearchLive(ctx context.Context, param SearchParam) ([]LiveRoomResp, error) {
+	return crawler.searchLivePaginator(param).Collect(ctx)
+}
+
+func (crawler *Tiktok) SearchLiveStream(ctx context.Context, param SearchParam) iter.Seq2[LiveRoomResp, error] {
+	return crawler.searchLivePaginator(param).Seq(ctx)
+}
+
+// GetUserLive returns live room of username, it reports ErrNotFound
+// when the user has never been live. Ended room is returned with LiveStatusEnded
+func (crawler *Tiktok) GetUserLive(ctx context.Context, username string) (LiveRoomResp, error) {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/@" + strings.TrimPrefix(username, "@") + "/live"}
+
+	paginator := newPaginator(crawler.config, uri.String(), "*/api-live/user/room/*", SearchParam{MaxItems: 1}, decodeUserLive)
+	paginator.ReadySelector = `#tiktok-live-main-container, #app`
+	// live page of offline user is still readable
+	paginator.Check = checkCaptcha
+	paginator.Actions = []chromedp.Action{chromedp.ActionFunc(checkCaptcha)}
+	// page of user who has never been live may not ask for its room
+	paginator.PageTimeout = 15 * time.Second
+
+	rooms, err := paginator.Collect(ctx)
+	if err != nil {
+		return LiveRoomResp{}, err
+	}
+	if len(rooms) == 0 {
+		return LiveRoomResp{}, errNoLiveRoom(username)
+	}
+
+	return rooms[0], nil
+}
+
+// errNoLiveRoom reports user who has never been live
+func errNoLiveRoom(username string) error {
+	return crawler.NewError("tiktok", "*/api-live/user/room/*", crawler.ErrNotFound, errors.New(username+" has no live room"))
+}
+
+func (t *Tiktok) searchLivePaginator(param SearchParam) *crawler.Paginator[LiveRoomResp] {
+	tab := searchTabs[SearchTabLive]
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: tab.path}
+
+	query := uri.Query()
+	query.Add("q", param.Term)
+	query.Add("t", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	uri.RawQuery = query.Encode()
+
+	paginator := newPaginator(t.config, uri.String(), tab.pattern, param, decodeSearchLive)
+	paginator.ReadySelector = tab.readySelector
+	paginator.Stop = stopCondition(param, func(item LiveRoomResp) string {
+		return item.RoomId
+	}, nil)
+
+	return paginator
+}
+
+func decodeSearchLive(body []byte) ([]LiveRoomResp, bool, error) {
+	var rooms []LiveRoomResp
+	var searchResp GeneralResp[[]SearchLiveItemResp]
+	err := json.Unmarshal(body, &searchResp)
+	if err != nil {
+		return rooms, false, err
+	}
+	if err := statusError(searchResp.StatusCode); err != nil {
+		return rooms, false, err
+	}
+
+	for _, v := range searchResp.Data {
+		// room is json string inside json
+		if v.LiveInfo.RawData == "" {
+			continue
+		}
+		var room LiveRoomRawResp
+		if err := json.Unmarshal([]byte(v.LiveInfo.RawData), &room); err != nil {
+			return rooms, false, err
+		}
+
+		rooms = append(rooms, LiveRoomResp{
+			RoomId:      room.IdStr,
+			Title:       room.Title,
+			ViewerCount: room.UserCount,
+			StartTime:   room.CreateTime,
+			Host: UserInfoResp{
+				Uid:           room.Owner.IdStr,
+				Nickname:      room.Owner.Nickname,
+				Signature:     room.Owner.BioDescription,
+				FollowerCount: room.Owner.FollowInfo.FollowerCount,
+				UniqueId:      room.Owner.DisplayId,
+			},
+			Status: room.Status,
+		})
+	}
+
+	return rooms, searchResp.HasMore == 1, nil
+}
+
+func decodeUserLive(body []byte) ([]LiveRoomResp, bool, error) {
+	var roomResp UserLiveResp
+	err := json.Unmarshal(body, &roomResp)
+	if err != nil {
+		return []LiveRoomResp{}, false, err
+	}
+	if err := statusError(roomResp.StatusCode); err != nil {
+		return []LiveRoomResp{}, false, err
+	}
+
+	user := roomResp.Data.User
+	if user.RoomId == "" {
+		return []LiveRoomResp{}, false, nil
+	}
+
+	room := roomResp.Data.LiveRoom
+	return []LiveRoomResp{{
+		RoomId:      user.RoomId,
+		Title:       room.Title,
+		ViewerCount: room.LiveRoomStats.UserCount,
+		StartTime:   room.StartTime,
+		Host: UserInfoResp{
+			Uid:           user.Id,
+			Nickname:      user.Nickname,
+			Signature:     user.Signature,
+			FollowerCount: roomResp.Data.Stats.FollowerCount,
+			UniqueId:      user.UniqueId,
+		},
+		Status: room.Status,
+	}}, false, nil
+}
@@ -0,0 +1,75 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeSearchLive(t *testing.T) {
+	// room is json string inside json
+	body := []byte(`{"status_code":0,"has_more":1,"data":[
+		{"live_info":{"raw_data":"{\"id_str\":\"7400000000000000001\",\"title\":\"cat cafe live\",\"user_count\":321,\"create_time\":1700000000,\"status\":2,\"owner\":{\"id_str\":\"6800000000000000001\",\"nickname\":\"Cat Lover\",\"display_id\":\"catlover\",\"bio_description\":\"cats\",\"follow_info\":{\"follower_count\":12300}}}"}},
+		{"live_info":{"raw_data":""}}
+	]}`)
+
+	rooms, hasMore, err := decodeSearchLive(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	if len(rooms) != 1 {
+		t.Fatalf("rooms = %+v, want one room", rooms)
+	}
+
+	room := rooms[0]
+	if room.RoomId != "7400000000000000001" || room.ViewerCount != 321 || room.Status != LiveStatusLive {
+		t.Errorf("room = %+v", room)
+	}
+	if room.Host.UniqueId != "catlover" || room.Host.FollowerCount != 12300 {
+		t.Errorf("host = %+v", room.Host)
+	}
+}
+
+func TestDecodeSearchLiveInvalidRoom(t *testing.T) {
+	body := []byte(`{"status_code":0,"data":[{"live_info":{"raw_data":"{"}}]}`)
+
+	if _, _, err := decodeSearchLive(body); err == nil {
+		t.Fatal("decodeSearchLive() of invalid room returns no error")
+	}
+}
+
+func TestDecodeUserLive(t *testing.T) {
+	body := []byte(`{"statusCode":0,"data":{
+		"user":{"id":"6800000000000000001","nickname":"Cat Lover","uniqueId":"catlover","roomId":"7400000000000000001"},
+		"liveRoom":{"title":"cat cafe live","startTime":1700000000,"status":4,"liveRoomStats":{"userCount":0}},
+		"stats":{"followerCount":12300}
+	}}`)
+
+	rooms, hasMore, err := decodeUserLive(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasMore {
+		t.Error("hasMore = true, want false")
+	}
+	if len(rooms) != 1 || rooms[0].RoomId != "7400000000000000001" || rooms[0].Status != LiveStatusEnded {
+		t.Fatalf("rooms = %+v", rooms)
+	}
+	if rooms[0].Host.FollowerCount != 12300 {
+		t.Errorf("host = %+v", rooms[0].Host)
+	}
+}
+
+func TestDecodeUserLiveNoRoom(t *testing.T) {
+	rooms, _, err := decodeUserLive([]byte(`{"statusCode":0,"data":{"user":{"id":"6800000000000000001"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 0 {
+		t.Fatalf("rooms = %+v, want none", rooms)
+	}
+}
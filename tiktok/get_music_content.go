@@ -0,0 +1,80 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strings"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// GetMusicContent returns music of term and content using it.
+// Content crawled before failure is returned together with the error
+func (crawler *Tiktok) GetMusicContent(ctx context.Context, param SearchParam) (MusicContent, error) {
+	var result MusicContent
+	items, err := crawler.musicContentPaginator(param, &result.Music).Collect(ctx)
+	result.Items = items
+
+	return result, err
+}
+
+// GetMusicContentStream streams content using music, every item carries the music
+func (crawler *Tiktok) GetMusicContentStream(ctx context.Context, param SearchParam) iter.Seq2[MusicItemResp, error] {
+	return func(yield func(MusicItemResp, error) bool) {
+		// filled once the page is ready, before the first item
+		var music MusicResp
+		for item, err := range crawler.musicContentPaginator(param, &music).Seq(ctx) {
+			if !yield(MusicItemResp{Music: music, Content: item}, err) {
+				return
+			}
+		}
+	}
+}
+
+// musicContentPaginator returns paginator of music content,
+// music is filled once the page is ready
+func (t *Tiktok) musicContentPaginator(param SearchParam, music *MusicResp) *crawler.Paginator[ContentItemResp] {
+	paginator := newPaginator(t.config, musicURL(param.Term), "*/api/music/item_list/*", param, decodeItemList)
+	paginator.ReadySelector = `[data-e2e="music-item-list"]`
+	// Stop sees item together with its music, as it is streamed
+	stop := stopCondition(param, func(item MusicItemResp) string {
+		return item.Content.Id
+	}, nil)
+	paginator.Stop = func(item ContentItemResp) bool {
+		return stop(MusicItemResp{Music: *music, Content: item})
+	}
+
+	script := hydrationScript("webapp.music-detail")
+	paginator.Actions = append(paginator.Actions, crawler.EvaluateJSON(t.config.Cassette, "music", script, func(body []byte) error {
+		return decodeMusic(body, music)
+	}))
+
+	return paginator
+}
+
+// musicURL returns music page of music url or id
+func musicURL(term string) string {
+	if strings.HasPrefix(term, "http://") || strings.HasPrefix(term, "https://") {
+		return term
+	}
+
+	// title part of the path is not checked by tiktok
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/music/sound-" + term}
+	return uri.String()
+}
+
+func decodeMusic(body []byte, music *MusicResp) error {
+	var detail MusicDetailResp
+	if err := decodeHydration(body, &detail); err != nil {
+		return err
+	}
+
+	*music = detail.MusicInfo.Music
+	music.Stats = detail.MusicInfo.Stats
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeMusic(t *testing.T) {
+	var music MusicResp
+	err := decodeMusic([]byte(`{"statusCode":0,"musicInfo":{
+		"music":{"id":"6900000000000000001","title":"original sound","authorName":"catlover","duration":15,"original":true},
+		"stats":{"videoCount":42}
+	}}`), &music)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if music.Id != "6900000000000000001" || !music.Original || music.Duration != 15 || music.Stats.VideoCount != 42 {
+		t.Fatalf("music = %+v", music)
+	}
+}
+
+func TestMusicContentStop(t *testing.T) {
+	var music MusicResp
+	param := SearchParam{
+		StopAtID: "3",
+		Stop: func(item any) bool {
+			v := item.(MusicItemResp)
+			return v.Music.Original && v.Content.Desc == "ad"
+		},
+	}
+	paginator := (&Tiktok{}).musicContentPaginator(param, &music)
+	// filled by the page actions before the first item
+	music.Original = true
+
+	tests := []struct {
+		item ContentItemResp
+		want bool
+	}{
+		{ContentItemResp{Id: "1"}, false},
+		{ContentItemResp{Id: "2", Desc: "ad"}, true},
+		{ContentItemResp{Id: "3"}, true},
+	}
+	for _, tt := range tests {
+		if got := paginator.Stop(tt.item); got != tt.want {
+			t.Errorf("Stop(%+v) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestMusicURL(t *testing.T) {
+	tests := map[string]string{
+		"6900000000000000001": "https://tiktok.com/music/sound-6900000000000000001",
+		"https://www.tiktok.com/music/original-sound-6900000000000000001": "https://www.tiktok.com/music/original-sound-6900000000000000001",
+	}
+	for term, want := range tests {
+		if got := musicURL(term); got != want {
+			t.Errorf("musicURL(%s) = %s, want %s", term, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// GetRelatedContent returns "You may like" content of video page, term is content url or id.
+// It is bounded by Scroll and MaxItems
+func (crawler *Tiktok) GetRelatedContent(ctx context.Context, param SearchParam) ([]ContentItemResp, error) {
+	return crawler.relatedContentPaginator(param).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetRelatedContentStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error] {
+	return crawler.relatedContentPaginator(param).Seq(ctx)
+}
+
+func (t *Tiktok) relatedContentPaginator(param SearchParam) *crawler.Paginator[ContentItemResp] {
+	paginator := newPaginator(t.config, contentURL(param.Term), "*/api/related/item_list/*", param, decodeRelatedContent)
+	paginator.ReadySelector = `[data-e2e="browse-video-desc"], [data-e2e="video-desc"]`
+	// related list is scrolled inside its column
+	paginator.ScrollScript = `(() => {
+	const items = document.querySelectorAll('a[href*="/video/"], a[href*="/photo/"]');
+	if (items.length > 0) items[items.length - 1].scrollIntoView();
+	else window.scrollTo(0, document.body.scrollHeight);
+})()`
+	// related list ends without telling, scroll then loads nothing
+	paginator.PageTimeout = 10 * time.Second
+	paginator.Stop = stopCondition(param, contentID, nil)
+
+	return paginator
+}
+
+// decodeRelatedContent decodes related item list,
+// which has no hasMore, so a non empty page means more may be loaded
+// until PageTimeout passes
+func decodeRelatedContent(body []byte) ([]ContentItemResp, bool, error) {
+	var itemList ItemListResp
+	err := json.Unmarshal(body, &itemList)
+	if err != nil {
+		return []ContentItemResp{}, false, err
+	}
+	if err := statusError(itemList.StatusCode); err != nil {
+		return []ContentItemResp{}, false, err
+	}
+
+	return itemList.ItemList, itemList.HasMore || len(itemList.ItemList) > 0, nil
+}
@@ -0,0 +1,31 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeRelatedContent(t *testing.T) {
+	tests := []struct {
+		body    string
+		items   int
+		hasMore bool
+	}{
+		// related api has no hasMore, non empty page may be followed by more
+		{`{"statusCode":0,"itemList":[{"id":"1"},{"id":"2"}]}`, 2, true},
+		{`{"statusCode":0,"itemList":[]}`, 0, false},
+		{`{"statusCode":0,"itemList":[],"hasMore":true}`, 0, true},
+	}
+
+	for _, tt := range tests {
+		items, hasMore, err := decodeRelatedContent([]byte(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != tt.items || hasMore != tt.hasMore {
+			t.Errorf("decodeRelatedContent(%s) = %d items %v, want %d items %v", tt.body, len(items), hasMore, tt.items, tt.hasMore)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDecodeUserContent(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "user_content", "post_item_list-0001.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, hasMore, err := decodeUserContent(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasMore {
+		t.Error("hasMore = true, want false")
+	}
+	if ids := contentIDs(items); !slices.Equal(ids, []string{"7300000000000000013", "7300000000000000014"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+	if items[0].Author.UniqueId != "catlover" || items[0].CreateTime != 1700000100 {
+		t.Errorf("item = %+v", items[0])
+	}
+}
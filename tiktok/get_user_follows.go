@@ -0,0 +1,88 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// GetFollowers returns followers of username term, bounded by MaxItems
+func (crawler *Tiktok) GetFollowers(ctx context.Context, param SearchParam) ([]UserInfoResp, error) {
+	return crawler.followListPaginator(param, `[data-e2e="followers-count"]`).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetFollowersStream(ctx context.Context, param SearchParam) iter.Seq2[UserInfoResp, error] {
+	return crawler.followListPaginator(param, `[data-e2e="followers-count"]`).Seq(ctx)
+}
+
+// GetFollowing returns users followed by username term, bounded by MaxItems
+func (crawler *Tiktok) GetFollowing(ctx context.Context, param SearchParam) ([]UserInfoResp, error) {
+	return crawler.followListPaginator(param, `[data-e2e="following-count"]`).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetFollowingStream(ctx context.Context, param SearchParam) iter.Seq2[UserInfoResp, error] {
+	return crawler.followListPaginator(param, `[data-e2e="following-count"]`).Seq(ctx)
+}
+
+// followListPaginator returns paginator of follow list modal opened by clicking count
+func (t *Tiktok) followListPaginator(param SearchParam, count string) *crawler.Paginator[UserInfoResp] {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/@" + strings.TrimPrefix(param.Term, "@")}
+
+	paginator := newPaginator(t.config, uri.String(), "*/api/user/list/*", param, decodeUserList)
+	paginator.ReadySelector = count
+	// the list is scrolled inside the modal instead of the window
+	paginator.ScrollScript = `(() => {
+	const modal = document.querySelector('[data-e2e="follow-info-popup"], [role="dialog"]');
+	if (!modal) return;
+	const list = [...modal.querySelectorAll('*')].find(el => el.scrollHeight > el.clientHeight && getComputedStyle(el).overflowY !== 'visible') || modal;
+	list.scrollTop = list.scrollHeight;
+})()`
+	paginator.Actions = []chromedp.Action{
+		chromedp.Click(count, chromedp.ByQuery),
+		chromedp.ActionFunc(waitFollowList),
+	}
+	paginator.Stop = stopCondition(param, func(item UserInfoResp) string {
+		return item.Uid
+	}, nil)
+
+	return paginator
+}
+
+// waitFollowList waits follow list modal shown.
+// The modal never shows when the list is hidden by its owner
+func waitFollowList(ctx context.Context) error {
+	return waitShown(ctx, `[data-e2e="follow-info-popup"], [role="dialog"]`, 10*time.Second, "*/api/user/list/*")
+}
+
+func decodeUserList(body []byte) ([]UserInfoResp, bool, error) {
+	var items []UserInfoResp
+	var listResp UserListResp
+	err := json.Unmarshal(body, &listResp)
+	if err != nil {
+		return items, false, err
+	}
+	if err := statusError(listResp.StatusCode); err != nil {
+		return items, false, err
+	}
+
+	for _, v := range listResp.UserList {
+		items = append(items, UserInfoResp{
+			Uid:           v.User.Id,
+			Nickname:      v.User.Nickname,
+			Signature:     v.User.Signature,
+			FollowerCount: v.Stats.FollowerCount,
+			UniqueId:      v.User.UniqueId,
+		})
+	}
+
+	return items, listResp.HasMore, nil
+}
@@ -0,0 +1,25 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeUserList(t *testing.T) {
+	users, hasMore, err := decodeUserList([]byte(`{"statusCode":0,"hasMore":true,"minCursor":1700000000,"userList":[
+		{"user":{"id":"6800000000000000002","uniqueId":"kittydaily","nickname":"Kitty Daily","signature":"daily kitty"},"stats":{"followerCount":500}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+
+	want := UserInfoResp{Uid: "6800000000000000002", Nickname: "Kitty Daily", Signature: "daily kitty", FollowerCount: 500, UniqueId: "kittydaily"}
+	if len(users) != 1 || users[0] != want {
+		t.Fatalf("users = %+v, want %+v", users, want)
+	}
+}
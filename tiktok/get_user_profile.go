@@ -0,0 +1,28 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+func (crawler *Tiktok) GetUserProfile(ctx context.Context, username string) (UserProfileResp, error) {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/@" + strings.TrimPrefix(username, "@")}
+
+	return newDocument(crawler.config, uri.String(), "webapp.user-detail", decodeUserProfile).Fetch(ctx)
+}
+
+func decodeUserProfile(body []byte) (UserProfileResp, error) {
+	var detail UserDetailResp
+	if err := decodeHydration(body, &detail); err != nil {
+		return UserProfileResp{}, err
+	}
+
+	profile := detail.UserInfo.User
+	profile.Stats = detail.UserInfo.Stats
+
+	return profile, nil
+}
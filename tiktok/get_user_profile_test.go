@@ -0,0 +1,42 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+func TestDecodeUserProfile(t *testing.T) {
+	profile, err := decodeUserProfile([]byte(`{"statusCode":0,"userInfo":{
+		"user":{"id":"6800000000000000001","uniqueId":"catlover","nickname":"Cat Lover","secUid":"MS4wLjABAAAA","verified":true},
+		"stats":{"followerCount":12300,"videoCount":88}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.UniqueId != "catlover" || profile.SecUid != "MS4wLjABAAAA" || !profile.Verified {
+		t.Errorf("profile = %+v", profile)
+	}
+	if profile.Stats.FollowerCount != 12300 || profile.Stats.VideoCount != 88 {
+		t.Errorf("stats = %+v", profile.Stats)
+	}
+}
+
+func TestDecodeUserProfileStatus(t *testing.T) {
+	tests := map[string]error{
+		`{"statusCode":10202}`: crawler.ErrNotFound,
+		`{"statusCode":10222}`: crawler.ErrPrivate,
+		`{"statusCode":10000}`: crawler.ErrCaptcha,
+	}
+
+	for body, want := range tests {
+		if _, err := decodeUserProfile([]byte(body)); !errors.Is(err, want) {
+			t.Errorf("decodeUserProfile(%s) err = %v, want %v", body, err, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// userTab is tab of user profile besides videos
+type userTab struct {
+	// tab button
+	selector string
+	// item list shown once the tab is opened
+	list    string
+	pattern string
+}
+
+var (
+	repostsTab     = userTab{`[data-e2e="repost-tab"]`, `[data-e2e="user-repost-item-list"]`, "*/api/repost/item_list/*"}
+	likedTab       = userTab{`[data-e2e="liked-tab"]`, `[data-e2e="user-liked-item-list"]`, "*/api/favorite/item_list/*"}
+	collectionsTab = userTab{`[data-e2e="favorites-tab"]`, `[data-e2e="user-favorites-item-list"]`, "*/api/user/collect/item_list/*"}
+)
+
+// GetUserReposts returns content reposted by username term, bounded by Scroll and MaxItems
+func (crawler *Tiktok) GetUserReposts(ctx context.Context, param SearchParam) ([]ContentItemResp, error) {
+	return crawler.userTabPaginator(param, repostsTab).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetUserRepostsStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error] {
+	return crawler.userTabPaginator(param, repostsTab).Seq(ctx)
+}
+
+// GetUserLiked returns content liked by username term,
+// the tab is shown only when AuthorResp.OpenFavorite is true
+func (crawler *Tiktok) GetUserLiked(ctx context.Context, param SearchParam) ([]ContentItemResp, error) {
+	return crawler.userTabPaginator(param, likedTab).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetUserLikedStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error] {
+	return crawler.userTabPaginator(param, likedTab).Seq(ctx)
+}
+
+// GetUserCollections returns content saved on favorites tab of username term
+func (crawler *Tiktok) GetUserCollections(ctx context.Context, param SearchParam) ([]ContentItemResp, error) {
+	return crawler.userTabPaginator(param, collectionsTab).Collect(ctx)
+}
+
+func (crawler *Tiktok) GetUserCollectionsStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error] {
+	return crawler.userTabPaginator(param, collectionsTab).Seq(ctx)
+}
+
+// userTabPaginator returns paginator of item list opened by clicking profile tab
+func (t *Tiktok) userTabPaginator(param SearchParam, tab userTab) *crawler.Paginator[ContentItemResp] {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/@" + strings.TrimPrefix(param.Term, "@")}
+
+	paginator := newPaginator(t.config, uri.String(), tab.pattern, param, decodeItemList)
+	paginator.ReadySelector = `[data-e2e="followers-count"]`
+	paginator.Actions = []chromedp.Action{
+		chromedp.ActionFunc(tab.open),
+	}
+	// user content tabs are ordered by the time they are added, not created
+	paginator.Stop = stopCondition(param, contentID, nil)
+
+	return paginator
+}
+
+// open clicks the tab and waits its item list shown.
+// The tab is hidden, or shows no list, when it is private
+func (tab userTab) open(ctx context.Context) error {
+	var clicked bool
+	err := chromedp.Evaluate(`(() => {
+	const tab = document.querySelector('`+tab.selector+`');
+	if (!tab) return false;
+	tab.click();
+	return true;
+})()`, &clicked).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if !clicked {
+		return crawler.NewError("tiktok", tab.pattern, crawler.ErrPrivate, nil)
+	}
+
+	return waitShown(ctx, tab.list, 10*time.Second, tab.pattern)
+}
@@ -0,0 +1,33 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// hydrationScript returns expression of scope data embedded on tiktok page,
+// e.g "webapp.user-detail"
+func hydrationScript(scope string) string {
+	return fmt.Sprintf(
+		`JSON.parse(document.getElementById("__UNIVERSAL_DATA_FOR_REHYDRATION__").textContent).__DEFAULT_SCOPE__[%q]`,
+		scope,
+	)
+}
+
+// decodeHydration decodes scope data of hydrationScript into v
+func decodeHydration(body []byte, v any) error {
+	var status struct {
+		StatusCode int `json:"statusCode"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return err
+	}
+	if err := statusError(status.StatusCode); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,75 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSearchReplay(t *testing.T) {
+	tiktok := replayCrawler(t, "search")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	items, err := tiktok.Search(ctx, SearchParam{Term: "cat", Scroll: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"7300000000000000001", "7300000000000000002", "7300000000000000003"}
+	if ids := contentIDs(items); !slices.Equal(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+}
+
+func TestGetUserContentReplay(t *testing.T) {
+	tests := []struct {
+		name  string
+		param SearchParam
+		want  []string
+	}{
+		{
+			name:  "every page",
+			param: SearchParam{Term: "catlover", Scroll: 5},
+			want:  []string{"7200000000000000001", "7300000000000000011", "7300000000000000012", "7300000000000000013", "7300000000000000014"},
+		},
+		{
+			name:  "first page",
+			param: SearchParam{Term: "catlover", Scroll: 1},
+			want:  []string{"7200000000000000001", "7300000000000000011", "7300000000000000012"},
+		},
+		{
+			// pinned content is older, but does not stop the crawl
+			name:  "until",
+			param: SearchParam{Term: "catlover", Scroll: 5, Until: time.Unix(1700000150, 0)},
+			want:  []string{"7200000000000000001", "7300000000000000011", "7300000000000000012"},
+		},
+		{
+			name:  "max items",
+			param: SearchParam{Term: "catlover", Scroll: 5, MaxItems: 4},
+			want:  []string{"7200000000000000001", "7300000000000000011", "7300000000000000012", "7300000000000000013"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiktok := replayCrawler(t, "user_content")
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			items, err := tiktok.GetUserContent(ctx, tt.param)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := contentIDs(items); !slices.Equal(ids, tt.want) {
+				t.Fatalf("ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
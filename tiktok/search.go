@@ -6,169 +6,131 @@ package tiktok
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
-	"sync"
 	"time"
 
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/fetch"
-	"github.com/chromedp/cdproto/network"
-	"github.com/chromedp/cdproto/runtime"
-	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
 )
 
 func (crawler *Tiktok) Search(ctx context.Context, param SearchParam) ([]ContentItemResp, error) {
-	var cards []ContentItemResp
-	// Chrome options
-	opts, err := crawler.config.GetOpts()
+	paginator, err := crawler.searchPaginator(param)
 	if err != nil {
-		return cards, err
+		return nil, err
 	}
 
-	// Allocator
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	// Browser context
-	ctx, cancel = chromedp.NewContext(allocCtx)
-	defer cancel()
+	return paginator.Collect(ctx)
+}
 
-	uri, err := url.Parse(`https://tiktok.com/search`)
+func (crawler *Tiktok) SearchStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error] {
+	paginator, err := crawler.searchPaginator(param)
 	if err != nil {
-		return cards, nil
+		return errSeq[ContentItemResp](err)
 	}
 
+	return paginator.Seq(ctx)
+}
+
+func (t *Tiktok) searchPaginator(param SearchParam) (*crawler.Paginator[ContentItemResp], error) {
+	if err := validateSearch(param); err != nil {
+		return nil, err
+	}
+
+	tab := searchTabs[param.Tab]
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: tab.path}
+
 	query := uri.Query()
 	query.Add("q", param.Term)
 	query.Add("t", strconv.FormatInt(time.Now().UnixMilli(), 10))
 	uri.RawQuery = query.Encode()
-	listenErr := make(chan error, 1)
-	resultChannel := make(chan ContentItemResp, 1)
-	shouldScrollCh := make(chan bool, 1)
-	var totalLoad int = 0
-	var wg sync.WaitGroup
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for result := range resultChannel {
-			cards = append(cards, result)
+
+	paginator := newPaginator(t.config, uri.String(), tab.pattern, param, decodeSearchResult)
+	paginator.ReadySelector = tab.readySelector
+	paginator.Stop = stopCondition(param, contentID, nil)
+	if param.PublishedWithin != PublishTimeAll || param.SortBy != SortByRelevance {
+		paginator.Rewrite = func(uri string) string {
+			return searchFilterURL(uri, param)
 		}
-	}()
-
-	// // run task list
-	chromedp.ListenTarget(
-		ctx, func(ev any) {
-			switch ev := ev.(type) {
-			case *fetch.EventRequestPaused:
-				go crawler.collectSearchResult(ctx, ev, resultChannel, listenErr, shouldScrollCh, &totalLoad, int(param.Scroll))
-			}
-		},
-	)
-
-	err = chromedp.Run(ctx,
-		network.Enable(),
-		fetch.Enable().WithPatterns([]*fetch.RequestPattern{
-			{
-				URLPattern:   "*/search/general/full/*",
-				RequestStage: fetch.RequestStageResponse,
-			},
-		}),
-		chromedp.Navigate(uri.String()),
-		chromedp.WaitVisible(`[data-e2e="search_top-item-list"]`, chromedp.ByQuery),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			if param.Scroll < 1 {
-				return nil
-			}
-
-			for shouldScroll := range shouldScrollCh {
-				var nodes []*cdp.Node
-				err = chromedp.Nodes(`[id^="column-item-video-container-"]`, &nodes, chromedp.ByQueryAll).Do(ctx)
-				if err != nil {
-					return fmt.Errorf("tiktok crawler err : %w", err)
-				}
-
-				if !shouldScroll {
-					return nil
-				}
-				_, exp, err := runtime.Evaluate(`window.scrollTo(0,document.body.scrollHeight);`).Do(ctx)
-				if err != nil {
-					return fmt.Errorf("tiktok crawler err : %w", err)
-				}
-				if exp != nil {
-					return fmt.Errorf("tiktok crawler err : %s", exp.Error())
-				}
-			}
-
-			return nil
-		}),
-	)
-
-	close(listenErr)
-	close(resultChannel)
-	close(shouldScrollCh)
-
-	wg.Wait()
+	}
 
-	if err != nil {
-		return cards, err
+	return paginator, nil
+}
+
+type searchTab struct {
+	path          string
+	pattern       string
+	readySelector string
+}
+
+var searchTabs = map[SearchTab]searchTab{
+	SearchTabGeneral: {"/search", "*/search/general/full/*", `[data-e2e="search_top-item-list"]`},
+	SearchTabVideos:  {"/search/video", "*/search/item/full/*", `[data-e2e="search_video-item-list"]`},
+	SearchTabLive:    {"/search/live", "*/search/live/full/*", `[data-e2e="search_live-item-list"]`},
+	SearchTabPhotos:  {"/search/photo", "*/search/photo/full/*", `[data-e2e="search_photo-item-list"]`},
+}
+
+// validateSearch reports search param not supported by tiktok
+func validateSearch(param SearchParam) error {
+	if _, ok := searchTabs[param.Tab]; !ok {
+		return fmt.Errorf("search param err : invalid tab %q", param.Tab)
+	}
+	if param.Tab == SearchTabLive {
+		return errors.New("search param err : live tab has no content, use SearchLive")
 	}
 
-	if err := <-listenErr; err != nil {
-		return cards, err
+	switch param.PublishedWithin {
+	case PublishTimeAll, PublishTimeDay, PublishTimeWeek, PublishTimeMonth, PublishTimeThreeMonths, PublishTimeSixMonths:
+	default:
+		return fmt.Errorf("search param err : invalid published within %d days", param.PublishedWithin)
 	}
 
-	return cards, nil
+	switch param.SortBy {
+	case SortByRelevance, SortByLikes:
+	default:
+		return fmt.Errorf("search param err : invalid sort by %d", param.SortBy)
+	}
+
+	return nil
 }
 
-func (t *Tiktok) collectSearchResult(
-	ctx context.Context,
-	ev *fetch.EventRequestPaused,
-	resultCh chan<- ContentItemResp,
-	errCh chan<- error,
-	shouldScrollCh chan<- bool,
-	totalLoad *int,
-	maxScroll int,
-) {
-	hasMoreCh := make(chan bool, 1)
-	scroll := func(canNext <-chan bool) {
-		time.Sleep(2 * time.Second)
-		// regardless
-		*totalLoad = *totalLoad + 1
-
-		if *totalLoad >= maxScroll || !(<-canNext) {
-			shouldScrollCh <- false
-		} else {
-			shouldScrollCh <- true
-		}
-	}
-	defer close(hasMoreCh)
-
-	c := chromedp.FromContext(ctx)
-	e := cdp.WithExecutor(ctx, c.Target)
-	bByte, err := fetch.GetResponseBody(ev.RequestID).Do(e)
-	fetch.ContinueResponse(ev.RequestID).Do(e)
-	// essential for trigger WaitVisible
-	defer scroll(hasMoreCh)
+// searchFilterURL adds filters of param into search api url,
+// the same query sent by tiktok filter panel
+func searchFilterURL(uri string, param SearchParam) string {
+	u, err := url.Parse(uri)
 	if err != nil {
-		errCh <- fmt.Errorf("tiktok crawler err : %w", err)
-		hasMoreCh <- false
-		return
+		return uri
 	}
-	var searchResp GeneralResp[[]SearchContentItemResp]
-	err = json.Unmarshal(bByte, &searchResp)
+
+	query := u.Query()
+	query.Set("publish_time", strconv.Itoa(int(param.PublishedWithin)))
+	query.Set("sort_type", strconv.Itoa(int(param.SortBy)))
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func decodeSearchResult(body []byte) ([]ContentItemResp, bool, error) {
+	var items []ContentItemResp
+	var searchResp SearchResp
+	err := json.Unmarshal(body, &searchResp)
 	if err != nil {
-		errCh <- fmt.Errorf("tiktok crawler err : %w", err)
-		hasMoreCh <- false
-		return
+		return items, false, err
+	}
+	if err := statusError(searchResp.StatusCode); err != nil {
+		return items, false, err
 	}
 
 	for _, v := range searchResp.Data {
-		resultCh <- v.Item
+		// skip user and live cards mixed into result
+		if v.Item.Id == "" {
+			continue
+		}
+		items = append(items, v.Item)
 	}
+	items = append(items, searchResp.ItemList...)
 
-	hasMoreCh <- (searchResp.HasMore == 1)
-
+	return items, searchResp.HasMore == 1, nil
 }
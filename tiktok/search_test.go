@@ -0,0 +1,104 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+func TestDecodeSearchResult(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "search", "search_general_full-0000.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, hasMore, err := decodeSearchResult(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	// user card is skipped
+	if ids := contentIDs(items); !slices.Equal(ids, []string{"7300000000000000001", "7300000000000000002"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+	if items[0].ContentType != ContentTypeVideo || items[1].ContentType != ContentTypePhoto {
+		t.Errorf("content types = %s %s, want video photo", items[0].ContentType, items[1].ContentType)
+	}
+	if images := items[1].ImagePost.Images; len(images) != 2 || images[1].Index != 1 {
+		t.Errorf("images = %+v", images)
+	}
+}
+
+func TestDecodeSearchResultItemList(t *testing.T) {
+	// videos and photos tab
+	body := []byte(`{"status_code":0,"item_list":[{"id":"1"},{"id":"2"}],"has_more":0}`)
+
+	items, hasMore, err := decodeSearchResult(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasMore {
+		t.Error("hasMore = true, want false")
+	}
+	if ids := contentIDs(items); !slices.Equal(ids, []string{"1", "2"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+}
+
+func TestDecodeSearchResultStatus(t *testing.T) {
+	_, _, err := decodeSearchResult([]byte(`{"status_code":10000}`))
+	if !errors.Is(err, crawler.ErrCaptcha) {
+		t.Fatalf("err = %v, want ErrCaptcha", err)
+	}
+}
+
+func TestValidateSearch(t *testing.T) {
+	valid := []SearchParam{
+		{},
+		{Tab: SearchTabVideos, PublishedWithin: PublishTimeWeek, SortBy: SortByLikes},
+		{Tab: SearchTabPhotos, PublishedWithin: PublishTimeSixMonths},
+	}
+	for _, param := range valid {
+		if err := validateSearch(param); err != nil {
+			t.Errorf("validateSearch(%+v) = %v", param, err)
+		}
+	}
+
+	invalid := []SearchParam{
+		{Tab: "users"},
+		{Tab: SearchTabLive},
+		{PublishedWithin: 2},
+		{SortBy: 3},
+	}
+	for _, param := range invalid {
+		if err := validateSearch(param); err == nil {
+			t.Errorf("validateSearch(%+v) returns no error", param)
+		}
+	}
+}
+
+func TestSearchFilterURL(t *testing.T) {
+	uri := searchFilterURL("https://www.tiktok.com/api/search/general/full/?keyword=cat&offset=0", SearchParam{
+		PublishedWithin: PublishTimeMonth,
+		SortBy:          SortByLikes,
+	})
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("keyword") != "cat" || query.Get("publish_time") != "30" || query.Get("sort_type") != "1" {
+		t.Fatalf("searchFilterURL() = %s", uri)
+	}
+}
@@ -6,177 +6,53 @@ package tiktok
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
-	"sync"
 	"time"
 
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/fetch"
-	"github.com/chromedp/cdproto/network"
-	"github.com/chromedp/cdproto/runtime"
-	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
 )
 
 func (crawler *Tiktok) SearchUser(ctx context.Context, param SearchParam) ([]UserInfoResp, error) {
-	var cards []UserInfoResp
-	// Chrome options
-	opts, err := crawler.config.GetOpts()
-	if err != nil {
-		return cards, err
-	}
-
-	// Allocator
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
+	return crawler.searchUserPaginator(param).Collect(ctx)
+}
 
-	// Browser context
-	ctx, cancel = chromedp.NewContext(allocCtx)
-	defer cancel()
+func (crawler *Tiktok) SearchUserStream(ctx context.Context, param SearchParam) iter.Seq2[UserInfoResp, error] {
+	return crawler.searchUserPaginator(param).Seq(ctx)
+}
 
-	uri, err := url.Parse(`https://tiktok.com/search/user`)
-	if err != nil {
-		return cards, nil
-	}
+func (t *Tiktok) searchUserPaginator(param SearchParam) *crawler.Paginator[UserInfoResp] {
+	uri := url.URL{Scheme: "https", Host: "tiktok.com", Path: "/search/user"}
 
 	query := uri.Query()
 	query.Add("q", param.Term)
 	query.Add("t", strconv.FormatInt(time.Now().UnixMilli(), 10))
 	uri.RawQuery = query.Encode()
-	listenErr := make(chan error, 1)
-	resultChannel := make(chan UserInfoResp, 1)
-	shouldScrollCh := make(chan bool, 1)
-	var totalLoad int = 0
-	var wg sync.WaitGroup
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for result := range resultChannel {
-			cards = append(cards, result)
-		}
-	}()
-
-	// // run task list
-	chromedp.ListenTarget(
-		ctx, func(ev any) {
-			switch ev := ev.(type) {
-			case *fetch.EventRequestPaused:
-				go crawler.collectSearchUserResult(ctx, ev, resultChannel, listenErr, shouldScrollCh, &totalLoad, int(param.Scroll))
-			}
-		},
-	)
-
-	err = chromedp.Run(ctx,
-		network.Enable(),
-		fetch.Enable().WithPatterns([]*fetch.RequestPattern{
-			{
-				URLPattern:   "*/search/user/full/*",
-				RequestStage: fetch.RequestStageResponse,
-			},
-		}),
-		chromedp.Navigate(uri.String()),
-		chromedp.WaitVisible(`[data-e2e="search-user-container"]`, chromedp.ByQuery),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			if param.Scroll < 1 {
-				return nil
-			}
-
-			for shouldScroll := range shouldScrollCh {
-
-				var nodes []*cdp.Node
-				err = chromedp.Nodes(`[id^="search_user-item-user-link-"]`, &nodes, chromedp.ByQueryAll).Do(ctx)
-				if err != nil {
-					return fmt.Errorf("tiktok crawler err : %w", err)
-				}
-
-				if !shouldScroll {
-					return nil
-				}
-
-				_, exp, err := runtime.Evaluate(`window.scrollTo(0,document.body.scrollHeight);`).Do(ctx)
-				if err != nil {
-					return fmt.Errorf("tiktok crawler err : %w", err)
-				}
-				if exp != nil {
-					return fmt.Errorf("tiktok crawler err : %s", exp.Error())
-				}
-			}
-
-			return nil
-		}),
-	)
 
-	close(listenErr)
-	close(resultChannel)
-	close(shouldScrollCh)
+	paginator := newPaginator(t.config, uri.String(), "*/search/user/full/*", param, decodeSearchUserResult)
+	paginator.ReadySelector = `[data-e2e="search-user-container"]`
+	paginator.Stop = stopCondition(param, func(item UserInfoResp) string {
+		return item.Uid
+	}, nil)
 
-	wg.Wait()
-
-	if err != nil {
-		return cards, err
-	}
-
-	if err := <-listenErr; err != nil {
-		return cards, err
-	}
-
-	return cards, nil
+	return paginator
 }
 
-func (t *Tiktok) collectSearchUserResult(
-	ctx context.Context,
-	ev *fetch.EventRequestPaused,
-	resultCh chan<- UserInfoResp,
-	errCh chan<- error,
-	shouldScrollCh chan<- bool,
-	totalLoad *int,
-	maxScroll int,
-) {
-	// slog.Info("collectSearchResult", slog.Any("total_load", *totalLoad), slog.Any("max_scroll", maxScroll))
-	hasMoreCh := make(chan bool, 1)
-	scroll := func(canNext <-chan bool) {
-		time.Sleep(2 * time.Second)
-		// regardless
-		*totalLoad = *totalLoad + 1
-
-		if *totalLoad >= maxScroll || !(<-canNext) {
-			shouldScrollCh <- false
-		} else {
-			shouldScrollCh <- true
-		}
-	}
-	defer close(hasMoreCh)
-
-	c := chromedp.FromContext(ctx)
-	e := cdp.WithExecutor(ctx, c.Target)
-	bByte, err := fetch.GetResponseBody(ev.RequestID).Do(e)
-	defer scroll(hasMoreCh)
-	// essential for trigger WaitVisible
-	if err != nil {
-		errCh <- fmt.Errorf("tiktok crawler err : %w", err)
-		hasMoreCh <- false
-		return
-	}
-	err = fetch.ContinueResponse(ev.RequestID).Do(e)
-	if err != nil {
-		errCh <- fmt.Errorf("tiktok crawler err : %w", err)
-		hasMoreCh <- false
-		return
-	}
+func decodeSearchUserResult(body []byte) ([]UserInfoResp, bool, error) {
+	var items []UserInfoResp
 	var searchResp SearchUserResp
-	err = json.Unmarshal(bByte, &searchResp)
+	err := json.Unmarshal(body, &searchResp)
 	if err != nil {
-		errCh <- fmt.Errorf("tiktok crawler err : %w", err)
-		hasMoreCh <- false
-		return
+		return items, false, err
+	}
+	if err := statusError(searchResp.StatusCode); err != nil {
+		return items, false, err
 	}
 
 	for _, v := range searchResp.UserList {
-		resultCh <- v.UserInfo
+		items = append(items, v.UserInfo)
 	}
 
-	hasMoreCh <- (searchResp.HasMore == 1)
-
+	return items, searchResp.HasMore == 1, nil
 }
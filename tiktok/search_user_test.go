@@ -0,0 +1,25 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"testing"
+)
+
+func TestDecodeSearchUserResult(t *testing.T) {
+	users, hasMore, err := decodeSearchUserResult([]byte(`{"status_code":0,"has_more":0,"user_list":[
+		{"user_info":{"uid":"6800000000000000001","nickname":"Cat Lover","unique_id":"catlover","follower_count":12300}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasMore {
+		t.Error("hasMore = true, want false")
+	}
+
+	want := UserInfoResp{Uid: "6800000000000000001", Nickname: "Cat Lover", FollowerCount: 12300, UniqueId: "catlover"}
+	if len(users) != 1 || users[0] != want {
+		t.Fatalf("users = %+v, want %+v", users, want)
+	}
+}
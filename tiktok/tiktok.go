@@ -5,6 +5,8 @@ package tiktok
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/nandanurseptama/golang-crawler/crawler"
 )
@@ -16,6 +18,63 @@ type TiktokCrawler interface {
 	SearchUser(ctx context.Context, param SearchParam) ([]UserInfoResp, error)
 	// Get user content
 	GetUserContent(ctx context.Context, param SearchParam) ([]ContentItemResp, error)
+	// Get content comments, term is content url or id
+	GetContentComments(ctx context.Context, param SearchParam) ([]CommentItem, error)
+	// Get hashtag challenge and its content, term is hashtag name
+	GetHashtagContent(ctx context.Context, param SearchParam) (HashtagContent, error)
+	// Get music and content using it, term is music url or id
+	GetMusicContent(ctx context.Context, param SearchParam) (MusicContent, error)
+	// Get content of content url or id
+	GetContent(ctx context.Context, contentURLOrID string) (ContentItemResp, error)
+	// Get user profile of username
+	GetUserProfile(ctx context.Context, username string) (UserProfileResp, error)
+	// Get followers of user, term is username
+	GetFollowers(ctx context.Context, param SearchParam) ([]UserInfoResp, error)
+	// Get users followed by user, term is username
+	GetFollowing(ctx context.Context, param SearchParam) ([]UserInfoResp, error)
+	// Get content reposted by user, term is username
+	GetUserReposts(ctx context.Context, param SearchParam) ([]ContentItemResp, error)
+	// Get content liked by user, term is username
+	GetUserLiked(ctx context.Context, param SearchParam) ([]ContentItemResp, error)
+	// Get content saved to favorites by user, term is username
+	GetUserCollections(ctx context.Context, param SearchParam) ([]ContentItemResp, error)
+	// Get content related to a video, term is content url or id
+	GetRelatedContent(ctx context.Context, param SearchParam) ([]ContentItemResp, error)
+	// Get trending content of explore page, term is optional category
+	GetExplore(ctx context.Context, param SearchParam) ([]ExploreItemResp, error)
+	// Search live rooms by search parameter
+	SearchLive(ctx context.Context, param SearchParam) ([]LiveRoomResp, error)
+	// Get live room of username
+	GetUserLive(ctx context.Context, username string) (LiveRoomResp, error)
+
+	// Stream content of search result as soon as every page is loaded
+	SearchStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error]
+	// Stream user of search result as soon as every page is loaded
+	SearchUserStream(ctx context.Context, param SearchParam) iter.Seq2[UserInfoResp, error]
+	// Stream user content as soon as every page is loaded
+	GetUserContentStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error]
+	// Stream content comments as soon as every page is loaded
+	GetContentCommentsStream(ctx context.Context, param SearchParam) iter.Seq2[CommentItem, error]
+	// Stream hashtag content with its challenge as soon as every page is loaded
+	GetHashtagContentStream(ctx context.Context, param SearchParam) iter.Seq2[HashtagItemResp, error]
+	// Stream content using music with the music as soon as every page is loaded
+	GetMusicContentStream(ctx context.Context, param SearchParam) iter.Seq2[MusicItemResp, error]
+	// Stream followers as soon as every page is loaded
+	GetFollowersStream(ctx context.Context, param SearchParam) iter.Seq2[UserInfoResp, error]
+	// Stream followed users as soon as every page is loaded
+	GetFollowingStream(ctx context.Context, param SearchParam) iter.Seq2[UserInfoResp, error]
+	// Stream reposted content as soon as every page is loaded
+	GetUserRepostsStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error]
+	// Stream liked content as soon as every page is loaded
+	GetUserLikedStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error]
+	// Stream favorites content as soon as every page is loaded
+	GetUserCollectionsStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error]
+	// Stream related content as soon as every page is loaded
+	GetRelatedContentStream(ctx context.Context, param SearchParam) iter.Seq2[ContentItemResp, error]
+	// Stream explore content as soon as every page is loaded
+	GetExploreStream(ctx context.Context, param SearchParam) iter.Seq2[ExploreItemResp, error]
+	// Stream live rooms of search result as soon as every page is loaded
+	SearchLiveStream(ctx context.Context, param SearchParam) iter.Seq2[LiveRoomResp, error]
 }
 type Tiktok struct {
 	config crawler.Config
@@ -26,3 +85,98 @@ func NewCrawler(config crawler.Config) TiktokCrawler {
 		config: config,
 	}
 }
+
+// newPaginator returns paginator with tiktok scroll behaviour
+func newPaginator[T any](
+	config crawler.Config,
+	uri string,
+	pattern string,
+	param SearchParam,
+	decode func(body []byte) ([]T, bool, error),
+) *crawler.Paginator[T] {
+	return &crawler.Paginator[T]{
+		Config:       config,
+		Platform:     "tiktok",
+		URL:          uri,
+		Pattern:      pattern,
+		Decode:       decode,
+		ScrollScript: `window.scrollTo(0,document.body.scrollHeight);`,
+		Check:        checkPage,
+		Scroll:       scrollCount(param.Scroll),
+		Delay:        2 * time.Second,
+		MaxItems:     param.MaxItems,
+	}
+}
+
+// errSeq returns sequence yielding err only
+func errSeq[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
+
+// scrollCount returns paginator scroll of param scroll,
+// which counts loaded pages including the first one
+func scrollCount(scroll uint) uint {
+	if scroll < 1 {
+		return 0
+	}
+
+	return scroll - 1
+}
+
+// newDocument returns document reading scope of tiktok hydration data, e.g "webapp.user-detail"
+func newDocument[T any](
+	config crawler.Config,
+	uri string,
+	scope string,
+	decode func(body []byte) (T, error),
+) *crawler.Document[T] {
+	return &crawler.Document[T]{
+		Config:   config,
+		Platform: "tiktok",
+		URL:      uri,
+		// hydration data is part of the document
+		ReadySelector: "body",
+		Check:         checkCaptcha,
+		Name:          scope,
+		Script:        hydrationScript(scope),
+		Decode:        decode,
+	}
+}
+
+// stopCondition returns stop predicate of param.
+// createdAt reports false when item has no relevant creation time
+func stopCondition[T any](
+	param SearchParam,
+	id func(item T) string,
+	createdAt func(item T) (time.Time, bool),
+) func(item T) bool {
+	return func(item T) bool {
+		if param.StopAtID != "" && id(item) == param.StopAtID {
+			return true
+		}
+
+		if !param.Until.IsZero() && createdAt != nil {
+			if t, ok := createdAt(item); ok && t.Before(param.Until) {
+				return true
+			}
+		}
+
+		return param.Stop != nil && param.Stop(item)
+	}
+}
+
+func contentID(item ContentItemResp) string {
+	return item.Id
+}
+
+func contentCreatedAt(item ContentItemResp) (time.Time, bool) {
+	// pinned content is kept on top regardless of its age
+	if item.IsPinnedItem || item.CreateTime < 1 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(item.CreateTime, 0), true
+}
@@ -0,0 +1,90 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package tiktok
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// replayCrawler returns crawler replaying cassette of testdata/name,
+// the test is skipped when chrome of CHROME_PATH or PATH is not found
+func replayCrawler(t *testing.T, name string) *Tiktok {
+	t.Helper()
+
+	path := os.Getenv("CHROME_PATH")
+	if path == "" {
+		for _, name := range []string{"google-chrome", "chromium", "chromium-browser", "headless-shell", "chrome"} {
+			if found, err := exec.LookPath(name); err == nil {
+				path = found
+				break
+			}
+		}
+	}
+	if path == "" {
+		t.Skip("chrome is not found, set CHROME_PATH to run replay tests")
+	}
+
+	cassette := crawler.NewCassette(filepath.Join("testdata", name), true)
+	server := cassette.Serve()
+	t.Cleanup(server.Close)
+
+	return &Tiktok{config: crawler.Config{
+		ChromePath:         path,
+		Headless:           true,
+		DisableGPU:         true,
+		NoSandbox:          true,
+		DisableDevSHMUsage: true,
+		Cassette:           cassette,
+	}}
+}
+
+func contentIDs(items []ContentItemResp) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+
+	return ids
+}
+
+func TestScrollCount(t *testing.T) {
+	tests := map[uint]uint{0: 0, 1: 0, 2: 1, 10: 9}
+
+	for scroll, want := range tests {
+		if got := scrollCount(scroll); got != want {
+			t.Errorf("scrollCount(%d) = %d, want %d", scroll, got, want)
+		}
+	}
+}
+
+func TestStopCondition(t *testing.T) {
+	until := time.Unix(1700000150, 0)
+	tests := []struct {
+		name  string
+		param SearchParam
+		item  ContentItemResp
+		want  bool
+	}{
+		{"no condition", SearchParam{}, ContentItemResp{Id: "1", CreateTime: 1}, false},
+		{"stop at id", SearchParam{StopAtID: "1"}, ContentItemResp{Id: "1"}, true},
+		{"other id", SearchParam{StopAtID: "1"}, ContentItemResp{Id: "2"}, false},
+		{"older than until", SearchParam{Until: until}, ContentItemResp{CreateTime: 1700000100}, true},
+		{"newer than until", SearchParam{Until: until}, ContentItemResp{CreateTime: 1700000200}, false},
+		{"pinned older than until", SearchParam{Until: until}, ContentItemResp{CreateTime: 1600000000, IsPinnedItem: true}, false},
+		{"stop func", SearchParam{Stop: func(item any) bool { return item.(ContentItemResp).Desc == "ad" }}, ContentItemResp{Desc: "ad"}, true},
+	}
+
+	for _, tt := range tests {
+		stop := stopCondition(tt.param, contentID, contentCreatedAt)
+		if got := stop(tt.item); got != tt.want {
+			t.Errorf("%s : stop() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,13 +3,77 @@
 
 package tiktok
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type SearchParam struct {
 	// search term
 	Term string `json:"term"`
-	// Total scroll of page
+	// Total loaded page including the first one, e.g 3 scrolls the page twice
 	Scroll uint `json:""`
+
+	// Stop after this many items, zero means unlimited.
+	// Replies are not counted, see MaxReplies
+	MaxItems uint `json:"max_items"`
+	// Stop at first content created before this time,
+	// GetUserContent only, the other lists are not ordered by time.
+	// Pinned content is ignored
+	Until time.Time `json:"until"`
+	// Stop at this item id, e.g last item seen on previous run.
+	// The item itself is not returned
+	StopAtID string `json:"stop_at_id"`
+	// Optional, stop when it returns true.
+	// Item type follows the streamed item, e.g ContentItemResp on Search
+	// and HashtagItemResp on GetHashtagContent
+	Stop func(item any) bool `json:"-"`
+
+	// Search tab, Search only. Defaults to SearchTabGeneral
+	Tab SearchTab `json:"tab"`
+	// Only content posted within this period, Search only
+	PublishedWithin PublishTime `json:"published_within"`
+	// Search result order, Search only
+	SortBy SortBy `json:"sort_by"`
+
+	// Expand reply thread of every comment, GetContentComments only.
+	// Replies follow their parent comment
+	Replies bool `json:"replies"`
+	// Maximum replies of every thread, zero means unlimited
+	MaxReplies uint `json:"max_replies"`
 }
 
+// SearchTab of tiktok search page.
+// Live tab has no content, see SearchLive for its rooms
+type SearchTab string
+
+const (
+	SearchTabGeneral SearchTab = ""
+	SearchTabVideos  SearchTab = "video"
+	SearchTabLive    SearchTab = "live"
+	SearchTabPhotos  SearchTab = "photo"
+)
+
+// PublishTime is search period in days, sent as publish_time of search api
+type PublishTime int
+
+const (
+	PublishTimeAll         PublishTime = 0
+	PublishTimeDay         PublishTime = 1
+	PublishTimeWeek        PublishTime = 7
+	PublishTimeMonth       PublishTime = 30
+	PublishTimeThreeMonths PublishTime = 90
+	PublishTimeSixMonths   PublishTime = 180
+)
+
+// SortBy is search result order, sent as sort_type of search api
+type SortBy int
+
+const (
+	SortByRelevance SortBy = 0
+	SortByLikes     SortBy = 1
+)
+
 // Content represents extracted info
 type Content struct {
 	Type int64 `json:"type"`
@@ -44,6 +108,110 @@ type ContentItemResp struct {
 	Author       AuthorResp       `json:"author"`
 	AuthorStats  AuthorStatsResp  `json:"authorStats"`
 	TextLanguage string           `json:"textLanguage"`
+	IsPinnedItem bool             `json:"isPinnedItem"`
+	Music        MusicResp        `json:"music"`
+	Video        VideoResp        `json:"video"`
+	Challenges   []ChallengeResp  `json:"challenges"`
+	// Country code where the content was created
+	LocationCreated string `json:"locationCreated"`
+	DuetEnabled     bool   `json:"duetEnabled"`
+	StitchEnabled   bool   `json:"stitchEnabled"`
+	// Tagged place
+	Poi PoiResp `json:"poi"`
+	// Links attached to content, e.g product or effect
+	Anchors []AnchorResp `json:"anchors"`
+	IsAd    bool         `json:"isAd"`
+	// Images of photo post, nil on video
+	ImagePost *ImagePostResp `json:"imagePost,omitempty"`
+	// Either video or photo, see ImagePost
+	ContentType ContentType `json:"contentType"`
+
+	// Untouched item json, for fields not covered by this struct
+	RawJSON json.RawMessage `json:"-"`
+}
+
+func (item *ContentItemResp) UnmarshalJSON(b []byte) error {
+	// plain has no UnmarshalJSON, so it decodes as usual
+	type plain ContentItemResp
+	var v plain
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*item = ContentItemResp(v)
+	item.RawJSON = append(json.RawMessage(nil), b...)
+
+	item.ContentType = ContentTypeVideo
+	if item.ImagePost != nil && len(item.ImagePost.Images) > 0 {
+		item.ContentType = ContentTypePhoto
+		for i := range item.ImagePost.Images {
+			item.ImagePost.Images[i].Index = i
+		}
+	}
+
+	return nil
+}
+
+type ContentType string
+
+const (
+	ContentTypeVideo ContentType = "video"
+	// Photo post, image carousel with music
+	ContentTypePhoto ContentType = "photo"
+)
+
+type ImagePostResp struct {
+	Title  string      `json:"title"`
+	Cover  ImageResp   `json:"cover"`
+	Images []ImageResp `json:"images"`
+}
+
+type ImageResp struct {
+	ImageURL ImageURLResp `json:"imageURL"`
+	Width    int          `json:"imageWidth"`
+	Height   int          `json:"imageHeight"`
+	// Position of image on the carousel, starts from zero
+	Index int `json:"index"`
+}
+
+type ImageURLResp struct {
+	// Same image on different cdn
+	UrlList []string `json:"urlList"`
+}
+
+type PoiResp struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	City     string `json:"city"`
+	Province string `json:"province"`
+	Country  string `json:"country"`
+	Category string `json:"category"`
+}
+
+type AnchorResp struct {
+	Id string `json:"id"`
+	// Kind of anchor, e.g product or effect
+	Type        int    `json:"type"`
+	Keyword     string `json:"keyword"`
+	Description string `json:"description"`
+	// Link opened by the anchor
+	Schema string `json:"schema"`
+}
+
+type VideoResp struct {
+	Id string `json:"id"`
+	// duration in seconds
+	Duration     int    `json:"duration"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Ratio        string `json:"ratio"`
+	Cover        string `json:"cover"`
+	OriginCover  string `json:"originCover"`
+	DynamicCover string `json:"dynamicCover"`
+	PlayAddr     string `json:"playAddr"`
+	DownloadAddr string `json:"downloadAddr"`
+	Format       string `json:"format"`
 }
 
 type ContentStatsResp struct {
@@ -54,8 +222,18 @@ type ContentStatsResp struct {
 	ShareCount   uint64 `json:"shareCount"`
 }
 
+// Hashtag or user mentioned on content description
 type TextExtraResp struct {
+	// 0 is user mention, 1 is hashtag
+	Type       int    `json:"type"`
 	HastagName string `json:"hashtagName"`
+	HashtagId  string `json:"hashtagId"`
+	// Mentioned user
+	UserId       string `json:"userId"`
+	UserUniqueId string `json:"userUniqueId"`
+	// Position on description
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 type AuthorStatsResp struct {
@@ -90,6 +268,12 @@ type SearchUserItemResp struct {
 	UserInfo UserInfoResp `json:"user_info"`
 }
 
+// Search response of general tab in data, or videos and photos tab in item_list
+type SearchResp struct {
+	GeneralResp[[]SearchContentItemResp]
+	ItemList []ContentItemResp `json:"item_list"`
+}
+
 type SearchUserResp struct {
 	GeneralResp[[]SearchUserItemResp]
 	UserList []SearchUserItemResp `json:"user_list"`
@@ -99,3 +283,235 @@ type GetUserContentResp struct {
 	GeneralResp[[]SearchContentItemResp]
 	ItemList []SearchContentItemResp `json:"itemList"`
 }
+
+type CommentItem struct {
+	Id string `json:"cid"`
+	// Commented content id
+	ContentId string `json:"aweme_id"`
+	// Id of replied comment, empty on top level comment
+	ParentId   string          `json:"reply_id"`
+	Text       string          `json:"text"`
+	CreateTime int64           `json:"create_time"`
+	DiggCount  uint64          `json:"digg_count"`
+	ReplyCount uint64          `json:"reply_comment_total"`
+	Language   string          `json:"comment_language"`
+	User       CommentUserResp `json:"user"`
+}
+
+type CommentUserResp struct {
+	Uid      string `json:"uid"`
+	Nickname string `json:"nickname"`
+	// a.k.a username
+	UniqueId string `json:"unique_id"`
+}
+
+type GetCommentsResp struct {
+	StatusCode int           `json:"status_code"`
+	Comments   []CommentItem `json:"comments"`
+	Cursor     int           `json:"cursor"`
+	HasMore    int           `json:"has_more"`
+	Total      int           `json:"total"`
+}
+
+// Item list response of challenge, music and explore api
+type ItemListResp struct {
+	StatusCode int               `json:"statusCode"`
+	ItemList   []ContentItemResp `json:"itemList"`
+	HasMore    bool              `json:"hasMore"`
+	Cursor     string            `json:"cursor"`
+}
+
+type LiveStatus int
+
+const (
+	LiveStatusLive  LiveStatus = 2
+	LiveStatusEnded LiveStatus = 4
+)
+
+type LiveRoomResp struct {
+	RoomId      string `json:"roomId"`
+	Title       string `json:"title"`
+	ViewerCount uint64 `json:"viewerCount"`
+	// Unix time in seconds
+	StartTime int64        `json:"startTime"`
+	Host      UserInfoResp `json:"host"`
+	Status    LiveStatus   `json:"status"`
+}
+
+// Wrapper of search LIVE tab response
+type SearchLiveItemResp struct {
+	LiveInfo struct {
+		// LiveRoomRawResp encoded as json string
+		RawData string `json:"raw_data"`
+	} `json:"live_info"`
+}
+
+type LiveRoomRawResp struct {
+	IdStr      string     `json:"id_str"`
+	Title      string     `json:"title"`
+	UserCount  uint64     `json:"user_count"`
+	CreateTime int64      `json:"create_time"`
+	Status     LiveStatus `json:"status"`
+	Owner      struct {
+		IdStr          string `json:"id_str"`
+		Nickname       string `json:"nickname"`
+		DisplayId      string `json:"display_id"`
+		BioDescription string `json:"bio_description"`
+		FollowInfo     struct {
+			FollowerCount uint64 `json:"follower_count"`
+		} `json:"follow_info"`
+	} `json:"owner"`
+}
+
+// Response of user live room api
+type UserLiveResp struct {
+	StatusCode int `json:"statusCode"`
+	Data       struct {
+		User struct {
+			Id        string `json:"id"`
+			Nickname  string `json:"nickname"`
+			UniqueId  string `json:"uniqueId"`
+			Signature string `json:"signature"`
+			RoomId    string `json:"roomId"`
+		} `json:"user"`
+		LiveRoom struct {
+			Title         string     `json:"title"`
+			StartTime     int64      `json:"startTime"`
+			Status        LiveStatus `json:"status"`
+			LiveRoomStats struct {
+				UserCount uint64 `json:"userCount"`
+			} `json:"liveRoomStats"`
+		} `json:"liveRoom"`
+		Stats struct {
+			FollowerCount uint64 `json:"followerCount"`
+		} `json:"stats"`
+	} `json:"data"`
+}
+
+// Content of explore feed
+type ExploreItemResp struct {
+	// Selected category chip, empty on the default feed
+	Category string          `json:"category"`
+	Content  ContentItemResp `json:"content"`
+}
+
+type HashtagContent struct {
+	Challenge ChallengeResp     `json:"challenge"`
+	Items     []ContentItemResp `json:"items"`
+}
+
+// Streamed hashtag content together with its challenge
+type HashtagItemResp struct {
+	Challenge ChallengeResp   `json:"challenge"`
+	Content   ContentItemResp `json:"content"`
+}
+
+// Challenge a.k.a hashtag
+type ChallengeResp struct {
+	Id    string             `json:"id"`
+	Title string             `json:"title"`
+	Desc  string             `json:"desc"`
+	Stats ChallengeStatsResp `json:"stats"`
+}
+
+type ChallengeStatsResp struct {
+	VideoCount uint64 `json:"videoCount"`
+	ViewCount  uint64 `json:"viewCount"`
+}
+
+// Challenge data embedded on hashtag page
+type ChallengeDetailResp struct {
+	ChallengeInfo struct {
+		Challenge ChallengeResp      `json:"challenge"`
+		Stats     ChallengeStatsResp `json:"stats"`
+	} `json:"challengeInfo"`
+}
+
+type MusicContent struct {
+	Music MusicResp         `json:"music"`
+	Items []ContentItemResp `json:"items"`
+}
+
+// Streamed content using music together with the music
+type MusicItemResp struct {
+	Music   MusicResp       `json:"music"`
+	Content ContentItemResp `json:"content"`
+}
+
+type MusicResp struct {
+	Id         string `json:"id"`
+	Title      string `json:"title"`
+	AuthorName string `json:"authorName"`
+	// duration in seconds
+	Duration int `json:"duration"`
+	// Whether it is original sound of a content
+	Original   bool           `json:"original"`
+	PlayUrl    string         `json:"playUrl"`
+	CoverLarge string         `json:"coverLarge"`
+	Album      string         `json:"album"`
+	Stats      MusicStatsResp `json:"stats"`
+}
+
+type MusicStatsResp struct {
+	// Total content using the music
+	VideoCount uint64 `json:"videoCount"`
+}
+
+// Music data embedded on music page
+type MusicDetailResp struct {
+	MusicInfo struct {
+		Music MusicResp      `json:"music"`
+		Stats MusicStatsResp `json:"stats"`
+	} `json:"musicInfo"`
+}
+
+type UserProfileResp struct {
+	Id string `json:"id"`
+	// a.k.a username
+	UniqueId  string `json:"uniqueId"`
+	Nickname  string `json:"nickname"`
+	Signature string `json:"signature"`
+	// Secure user id required by other endpoints
+	SecUid         string          `json:"secUid"`
+	AvatarLarger   string          `json:"avatarLarger"`
+	AvatarMedium   string          `json:"avatarMedium"`
+	AvatarThumb    string          `json:"avatarThumb"`
+	Verified       bool            `json:"verified"`
+	PrivateAccount bool            `json:"privateAccount"`
+	BioLink        BioLinkResp     `json:"bioLink"`
+	Region         string          `json:"region"`
+	Language       string          `json:"language"`
+	CreateTime     int64           `json:"createTime"`
+	Stats          AuthorStatsResp `json:"stats"`
+}
+
+type BioLinkResp struct {
+	Link string `json:"link"`
+}
+
+// User data embedded on profile page
+type UserDetailResp struct {
+	UserInfo struct {
+		User  UserProfileResp `json:"user"`
+		Stats AuthorStatsResp `json:"stats"`
+	} `json:"userInfo"`
+}
+
+// Response of follower and following list api
+type UserListResp struct {
+	StatusCode int `json:"statusCode"`
+	UserList   []struct {
+		User  AuthorResp      `json:"user"`
+		Stats AuthorStatsResp `json:"stats"`
+	} `json:"userList"`
+	HasMore   bool  `json:"hasMore"`
+	MinCursor int64 `json:"minCursor"`
+	Total     int   `json:"total"`
+}
+
+// Content data embedded on content page
+type VideoDetailResp struct {
+	ItemInfo struct {
+		ItemStruct ContentItemResp `json:"itemStruct"`
+	} `json:"itemInfo"`
+}
@@ -0,0 +1,51 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"context"
+
+	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// pageStateScript returns state of youtube page which blocks crawling
+const pageStateScript = `(() => {
+	if (location.hostname.startsWith("consent.")) return "consent-required";
+	if (location.hostname.endsWith("google.com")) return "captcha";
+	if (document.querySelector("#error-page")) return "not-found";
+
+	const reason = document.querySelector("yt-playability-error-supported-renderers #reason, ytd-player-error-message-renderer #reason");
+	if (reason && reason.offsetParent !== null) {
+		const text = reason.textContent.toLowerCase();
+		if (text.includes("sign in")) return "login-required";
+		if (text.includes("private")) return "private";
+		return "not-found";
+	}
+
+	return "";
+})()`
+
+var pageStates = map[string]error{
+	"captcha":          crawler.ErrCaptcha,
+	"consent-required": crawler.ErrConsentRequired,
+	"not-found":        crawler.ErrNotFound,
+	"login-required":   crawler.ErrLoginRequired,
+	"private":          crawler.ErrPrivate,
+}
+
+// checkPage reports error when youtube page shows captcha, consent or unavailable state
+func checkPage(ctx context.Context) error {
+	var state string
+	err := chromedp.Evaluate(pageStateScript, &state).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if kind, ok := pageStates[state]; ok {
+		return crawler.NewError("youtube", "", kind, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDecodeContentComments(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "content_comments", "youtubei_v1_next-0000.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments, hasMore, err := decodeContentComments(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	// mutation other than comment is skipped
+	if ids := commentIDs(comments); !slices.Equal(ids, []string{"UgxComment1", "UgxComment2"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+
+	want := CommentItem{
+		ID:            "UgxComment1",
+		Content:       "The second cat is me on monday",
+		PublishedTime: "2 days ago",
+		Channel:       Channel{Name: "@mondaycat", ID: "UCmondaycat", Endpoint: "/@mondaycat"},
+		LikeCount:     120,
+		ReplyCount:    2,
+	}
+	if comments[0] != want {
+		t.Errorf("comment = %+v, want %+v", comments[0], want)
+	}
+}
+
+func TestDecodeContentCommentsLastPage(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "content_comments", "youtubei_v1_next-0001.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments, hasMore, err := decodeContentComments(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasMore {
+		t.Error("hasMore = true, want false")
+	}
+	if ids := commentIDs(comments); !slices.Equal(ids, []string{"UgxComment3"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+}
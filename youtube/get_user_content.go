@@ -1,226 +1,72 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
 package youtube
 
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"iter"
 	"net/url"
-	"strings"
-	"sync"
-	"time"
 
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/fetch"
-	"github.com/chromedp/cdproto/network"
-	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
 )
 
 func (crawler *Youtube) GetUserContent(ctx context.Context, param SearchContentParam) ([]UserContentItem, error) {
-	var results []UserContentItem
-	// Chrome options
-	opts, err := crawler.config.GetOpts()
-	if err != nil {
-		return results, err
-	}
-
-	// Allocator
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	// Browser context
-	ctx, cancel = chromedp.NewContext(allocCtx)
-	defer cancel()
-
-	uri, err := url.Parse(fmt.Sprintf("https://youtube.com/@%s/videos", param.Term))
-	if err != nil {
-		return results, nil
-	}
-	listenErr := make(chan error, 1)
-	resultChannel := make(chan UserContentItem, 1)
-	shouldScrollCh := make(chan bool, 1)
-	var totalLoad int = 0
-	var wg sync.WaitGroup
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for result := range resultChannel {
-			results = append(results, result)
-		}
-	}()
-
-	// listen target
-	chromedp.ListenTarget(
-		ctx, func(ev any) {
-			switch ev := ev.(type) {
-			case *fetch.EventRequestPaused:
-				//slog.Info("On EventRequestPaused", slog.Any("url", ev.Request.URL))
-				if strings.Contains(ev.Request.URL, "youtubei/v1/browse") {
-					go crawler.collectFromUserContentAPI(
-						ctx, ev, resultChannel,
-						listenErr,
-						shouldScrollCh,
-						&totalLoad,
-						int(param.Scroll),
-						param.DelayScrollDuration,
-					)
-				}
-			}
-		},
-	)
-
-	err = chromedp.Run(ctx,
-		network.Enable(),
-		fetch.Enable().WithPatterns([]*fetch.RequestPattern{
-			{
-				URLPattern:   "*youtubei/v1/browse*",
-				RequestStage: fetch.RequestStageResponse,
-			},
-		}),
-		chromedp.Navigate(uri.String()),
-
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var wg sync.WaitGroup
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				//slog.Info("listening should scroll")
-				for shouldScroll := range shouldScrollCh {
-					//slog.Info("onReceive shouldscroll", slog.Any("value", shouldScroll))
-					var nodes []*cdp.Node
-					err = chromedp.Nodes(`ytd-rich-grid-renderer div#contents ytd-rich-item-renderer`, &nodes, chromedp.ByQueryAll).Do(ctx)
-					if err != nil {
-						listenErr <- fmt.Errorf("youtube crawler err : %w", err)
-						return
-					}
-
-					if !shouldScroll {
-						return
-					}
-
-					var res []byte
-
-					err := chromedp.Evaluate(`window.scrollTo(0,document.querySelector("ytd-rich-grid-renderer div#contents").scrollHeight);`, &res).Do(ctx)
-					//slog.Info("tryScrolling")
-					if err != nil {
-						listenErr <- fmt.Errorf("youtube crawler err : %w", err)
-						return
-					}
-
-					//slog.Info("scroll finish")
-				}
-			}()
-
-			err := chromedp.WaitVisible(`ytd-rich-grid-renderer div#contents ytd-rich-item-renderer`, chromedp.ByQuery).Do(ctx)
-
-			if err != nil {
-				return err
-			}
-
-			results, err = crawler.collectFromUserContentInitialData(ctx)
-			//slog.Info("collect initial data finish")
-
-			if err != nil {
-				return err
-			}
-			//slog.Info("get initial data success", slog.Any("resultLength", len(results)))
-
-			time.Sleep(param.DelayScrollDuration)
-			shouldScrollCh <- true
-			wg.Wait()
-
-			return nil
-		}),
-	)
-	close(listenErr)
-	close(resultChannel)
-	close(shouldScrollCh)
-
-	wg.Wait()
-
-	if err != nil {
-		return results, err
-	}
+	return crawler.userContentPaginator(param).Collect(ctx)
+}
 
-	if err := <-listenErr; err != nil {
-		return results, err
-	}
+func (crawler *Youtube) GetUserContentStream(ctx context.Context, param SearchContentParam) iter.Seq2[UserContentItem, error] {
+	return crawler.userContentPaginator(param).Seq(ctx)
+}
 
-	return results, nil
+func (yt *Youtube) userContentPaginator(param SearchContentParam) *crawler.Paginator[UserContentItem] {
+	uri := url.URL{Scheme: "https", Host: "youtube.com", Path: "/@" + param.Term + "/videos"}
+
+	paginator := newPaginator(yt.config, uri.String(), "*youtubei/v1/browse*", param, decodeUserContent)
+	paginator.ReadySelector = `ytd-rich-grid-renderer div#contents ytd-rich-item-renderer`
+	paginator.ScrollScript = `window.scrollTo(0,document.querySelector("ytd-rich-grid-renderer div#contents").scrollHeight);`
+	paginator.InitialScript = `ytInitialData`
+	// first api page is also loaded by scroll
+	paginator.Scroll = param.Scroll + 1
+	paginator.DecodeInitial = decodeUserContentInitialData
+	paginator.Stop = stopCondition(param, func(item UserContentItem) string {
+		return item.ID
+	}, func(item UserContentItem) string {
+		return item.PublishedTime
+	})
+
+	return paginator
 }
 
-// collect initial data from youtube page
-func (crawler *Youtube) collectFromUserContentInitialData(ctx context.Context) ([]UserContentItem, error) {
+// decode initial data of youtube page
+func decodeUserContentInitialData(body []byte) ([]UserContentItem, bool, error) {
 	var ytInitialData UserContentYtInitialDataResp
-	err := chromedp.Evaluate(`ytInitialData`, &ytInitialData).Do(ctx)
-
+	err := json.Unmarshal(body, &ytInitialData)
 	if err != nil {
-		return []UserContentItem{}, err
+		return []UserContentItem{}, false, err
 	}
 
-	return ytInitialData.ToUserVideoItems(), nil
+	return ytInitialData.ToUserVideoItems(), true, nil
 }
 
-func (crawler *Youtube) collectFromUserContentAPI(
-	ctx context.Context,
-	ev *fetch.EventRequestPaused,
-	resultCh chan<- UserContentItem,
-	errCh chan<- error,
-	shouldScrollCh chan<- bool,
-	totalLoad *int,
-	maxScroll int,
-	scrollDelayDuration time.Duration,
-) {
-	//slog.Info("collect from API", slog.Any("totalLoad", *totalLoad))
-	scroll := func() {
-		time.Sleep(scrollDelayDuration)
-
-		if *totalLoad >= maxScroll {
-			shouldScrollCh <- false
-		} else {
-			shouldScrollCh <- true
-		}
-
-		// regardless
-		*totalLoad = *totalLoad + 1
-	}
-
-	c := chromedp.FromContext(ctx)
-	e := cdp.WithExecutor(ctx, c.Target)
-	bByte, err := fetch.GetResponseBody(ev.RequestID).Do(e)
-	defer scroll()
-	// essential for trigger WaitVisible
-	if err != nil {
-		errCh <- fmt.Errorf("youtube crawler err : %w", err)
-		return
-	}
-	err = fetch.ContinueResponse(ev.RequestID).Do(e)
-	if err != nil {
-		errCh <- fmt.Errorf("youtube crawler err : %w", err)
-		return
-	}
+func decodeUserContent(body []byte) ([]UserContentItem, bool, error) {
+	var results []UserContentItem
 	var searchResp SearchUserContentApiResp
-	err = json.Unmarshal(bByte, &searchResp)
+	err := json.Unmarshal(body, &searchResp)
 	if err != nil {
-		errCh <- fmt.Errorf("youtube crawler err : %w", err)
-		return
+		return results, false, err
 	}
 
-	// slog.Info("get response body succes", slog.Any("contentLength", len(bByte)))
-
-	// slog.Info("load commands", slog.Any("commandsLength", len(searchResp.OnResponseReceiveCommands)))
 	for _, command := range searchResp.OnResponseReceivedActions {
-		//slog.Info("load continueItem", slog.Any("continueItemLength", len(command.AppendContinuationItemsAction.ContinuationItems)))
 		for _, continueItem := range command.AppendContinuationItemsAction.ContinuationItems {
-			//slog.Info("load videoItem", slog.Any("videoItemLength", len(continueItem.ItemSectionRenderer.GetVideoItems())))
 			v := continueItem.RichItemRenderer.Content.VideoRenderer.ToVideoItem()
 			if v.ID == "" {
 				continue
 			}
-			resultCh <- v
-
+			results = append(results, v)
 		}
 	}
 
-	// slog.Info("finish collect from API")
+	return results, true, nil
 }
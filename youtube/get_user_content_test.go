@@ -0,0 +1,54 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeUserContentInitialData(t *testing.T) {
+	items, hasMore, err := decodeUserContentInitialData([]byte(`{"contents":{"twoColumnBrowseResultsRenderer":{"tabs":[
+		{"tabRenderer":{"content":{"richGridRenderer":{"contents":[
+			{"richItemRenderer":{"content":{"videoRenderer":{"videoId":"vid0000001a","title":{"runs":[{"text":"Funny cats compilation"}]},"lengthText":{"simpleText":"10:05"},"viewCountText":{"simpleText":"1,234 views"},"publishedTimeText":{"simpleText":"2 years ago"},"descriptionSnippet":{"runs":[{"text":"cats"}]}}}}},
+			{"continuationItemRenderer":{}}
+		]}}}},
+		{"tabRenderer":{}}
+	]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+
+	want := UserContentItem{
+		ID:            "vid0000001a",
+		Duration:      605,
+		DurationText:  "10:05",
+		ViewCount:     1234,
+		ViewCountText: "1,234 views",
+		Title:         "Funny cats compilation",
+		Desc:          "cats",
+		PublishedTime: "2 years ago",
+	}
+	if len(items) != 1 || !reflect.DeepEqual(items[0], want) {
+		t.Fatalf("items = %+v, want %+v", items, want)
+	}
+}
+
+func TestDecodeUserContent(t *testing.T) {
+	items, _, err := decodeUserContent([]byte(`{"onResponseReceivedActions":[{"appendContinuationItemsAction":{"continuationItems":[
+		{"richItemRenderer":{"content":{"videoRenderer":{"videoId":"vid0000002b"}}}},
+		{"richItemRenderer":{"content":{"videoRenderer":{"videoId":"vid0000003c"}}}},
+		{"continuationItemRenderer":{}}
+	]}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 || items[0].ID != "vid0000002b" || items[1].ID != "vid0000003c" {
+		t.Fatalf("items = %+v", items)
+	}
+}
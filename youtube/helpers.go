@@ -6,6 +6,7 @@ package youtube
 import (
 	"strconv"
 	"strings"
+	"time"
 )
 
 func parseViewCount(val string) uint64 {
@@ -45,3 +46,36 @@ func parseDurationToSeconds(input string) uint64 {
 
 	return h*3600 + m*60 + s
 }
+
+// parsePublishedTime approximates time of relative text,
+// e.g "3 days ago", "Streamed 2 weeks ago" or "1 year ago (edited)"
+func parsePublishedTime(input string, now time.Time) (time.Time, bool) {
+	fields := strings.Fields(strings.ToLower(input))
+	for i := 0; i+1 < len(fields); i++ {
+		value, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		unit := strings.TrimSuffix(fields[i+1], "s")
+		n := int(value)
+		switch unit {
+		case "second":
+			return now.Add(-time.Duration(n) * time.Second), true
+		case "minute":
+			return now.Add(-time.Duration(n) * time.Minute), true
+		case "hour":
+			return now.Add(-time.Duration(n) * time.Hour), true
+		case "day":
+			return now.AddDate(0, 0, -n), true
+		case "week":
+			return now.AddDate(0, 0, -7*n), true
+		case "month":
+			return now.AddDate(0, -n, 0), true
+		case "year":
+			return now.AddDate(-n, 0, 0), true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,68 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePublishedTime(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  time.Time
+		ok    bool
+	}{
+		{"30 seconds ago", now.Add(-30 * time.Second), true},
+		{"1 minute ago", now.Add(-time.Minute), true},
+		{"5 hours ago", now.Add(-5 * time.Hour), true},
+		{"3 days ago", now.AddDate(0, 0, -3), true},
+		{"2 weeks ago", now.AddDate(0, 0, -14), true},
+		{"1 month ago", now.AddDate(0, -1, 0), true},
+		{"4 years ago", now.AddDate(-4, 0, 0), true},
+		{"Streamed 2 weeks ago", now.AddDate(0, 0, -14), true},
+		{"1 year ago (edited)", now.AddDate(-1, 0, 0), true},
+		{"", time.Time{}, false},
+		{"yesterday", time.Time{}, false},
+		{"3 fortnights ago", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parsePublishedTime(tt.input, now)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parsePublishedTime(%q) = %v %v, want %v %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseViewCount(t *testing.T) {
+	tests := map[string]uint64{
+		"":                0,
+		"1,234,567 views": 1234567,
+		"42 views":        42,
+		"No views":        0,
+	}
+
+	for input, want := range tests {
+		if got := parseViewCount(input); got != want {
+			t.Errorf("parseViewCount(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
+
+func TestParseDurationToSeconds(t *testing.T) {
+	tests := map[string]uint64{
+		"45":      45,
+		"10:05":   605,
+		"1:02:03": 3723,
+		"1:2:3:4": 0,
+	}
+
+	for input, want := range tests {
+		if got := parseDurationToSeconds(input); got != want {
+			t.Errorf("parseDurationToSeconds(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func videoIDs(items []VideoItem) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
+
+func commentIDs(items []CommentItem) []string {
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
+
+func TestSearchContentReplay(t *testing.T) {
+	tests := []struct {
+		name  string
+		param SearchContentParam
+		want  []string
+	}{
+		{
+			// first api page is loaded by the first scroll
+			name:  "no scroll",
+			param: SearchContentParam{Term: "cat"},
+			want:  []string{"vid0000001a", "vid0000002b", "vid0000003c", "vid0000004d"},
+		},
+		{
+			name:  "scroll",
+			param: SearchContentParam{Term: "cat", Scroll: 1},
+			want:  []string{"vid0000001a", "vid0000002b", "vid0000003c", "vid0000004d", "vid0000005e"},
+		},
+		{
+			name:  "stop at id",
+			param: SearchContentParam{Term: "cat", Scroll: 1, StopAtID: "vid0000004d"},
+			want:  []string{"vid0000001a", "vid0000002b", "vid0000003c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			youtube := replayCrawler(t, "search_content")
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			items, err := youtube.SearchContent(ctx, tt.param)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := videoIDs(items); !slices.Equal(ids, tt.want) {
+				t.Fatalf("ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetContentCommentsReplay(t *testing.T) {
+	youtube := replayCrawler(t, "content_comments")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	comments, err := youtube.GetContentComments(ctx, SearchContentParam{Term: "vid0000001a", Scroll: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"UgxComment1", "UgxComment2", "UgxComment3"}
+	if ids := commentIDs(comments); !slices.Equal(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+}
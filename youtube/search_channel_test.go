@@ -0,0 +1,45 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"testing"
+)
+
+func TestDecodeChannelInitialData(t *testing.T) {
+	items, _, err := decodeChannelInitialData([]byte(`{"contents":{"twoColumnSearchResultsRenderer":{"primaryContents":{"sectionListRenderer":{"contents":[
+		{"itemSectionRenderer":{"contents":[
+			{"channelRenderer":{"channelId":"UCcatchannel","videoCountText":{"simpleText":"1.2M subscribers"},"shortBylineText":{"runs":[{"text":"Cat Channel","navigationEndpoint":{"browseEndpoint":{"browseId":"UCcatchannel","canonicalBaseUrl":"/@catchannel"}}}]},"descriptionSnippet":{"runs":[{"text":"all about cats"}]}}},
+			{"videoRenderer":{"videoId":"vid0000001a"}}
+		]}}
+	]}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("items = %+v, want one channel", items)
+	}
+	item := items[0]
+	if item.ID != "UCcatchannel" || item.Name != "Cat Channel" || item.Endpoint != "/@catchannel" ||
+		item.Description != "all about cats" || item.SubscriberCountText != "1.2M subscribers" {
+		t.Fatalf("item = %+v", item)
+	}
+}
+
+func TestDecodeSearchChannel(t *testing.T) {
+	items, hasMore, err := decodeSearchChannel([]byte(`{"onResponseReceivedCommands":[{"appendContinuationItemsAction":{"continuationItems":[
+		{"itemSectionRenderer":{"contents":[{"channelRenderer":{"channelId":"UCdoorcat"}},{"channelRenderer":{"channelId":"UCsnowcat"}}]}}
+	]}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+
+	if len(items) != 2 || items[0].ID != "UCdoorcat" || items[1].ID != "UCsnowcat" {
+		t.Fatalf("items = %+v", items)
+	}
+}
@@ -6,226 +6,65 @@ package youtube
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"iter"
 	"net/url"
-	"strings"
-	"sync"
-	"time"
 
-	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/fetch"
-	"github.com/chromedp/cdproto/network"
-	"github.com/chromedp/chromedp"
+	"github.com/nandanurseptama/golang-crawler/crawler"
 )
 
 func (crawler *Youtube) SearchContent(ctx context.Context, param SearchContentParam) ([]VideoItem, error) {
-	var results []VideoItem
-	// Chrome options
-	opts, err := crawler.config.GetOpts()
-	if err != nil {
-		return results, err
-	}
+	return crawler.searchContentPaginator(param).Collect(ctx)
+}
 
-	// Allocator
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
+func (crawler *Youtube) SearchContentStream(ctx context.Context, param SearchContentParam) iter.Seq2[VideoItem, error] {
+	return crawler.searchContentPaginator(param).Seq(ctx)
+}
 
-	// Browser context
-	ctx, cancel = chromedp.NewContext(allocCtx)
-	defer cancel()
+func (yt *Youtube) searchContentPaginator(param SearchContentParam) *crawler.Paginator[VideoItem] {
+	uri := url.URL{Scheme: "https", Host: "youtube.com", Path: "/results"}
 
-	uri, err := url.Parse(`https://youtube.com/results`)
-	if err != nil {
-		return results, nil
-	}
 	query := uri.Query()
 	query.Add("search_query", param.Term)
 	uri.RawQuery = query.Encode()
 
-	listenErr := make(chan error, 1)
-	resultChannel := make(chan VideoItem, 1)
-	shouldScrollCh := make(chan bool, 1)
-	var totalLoad int = 0
-	var wg sync.WaitGroup
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for result := range resultChannel {
-			results = append(results, result)
-		}
-	}()
-
-	// listen target
-	chromedp.ListenTarget(
-		ctx, func(ev any) {
-			switch ev := ev.(type) {
-			case *fetch.EventRequestPaused:
-				// slog.Info("On EventRequestPaused", slog.Any("url", ev.Request.URL))
-				if strings.Contains(ev.Request.URL, "youtubei/v1/search") {
-					go crawler.collectFromSearchContentAPI(
-						ctx, ev, resultChannel,
-						listenErr,
-						shouldScrollCh,
-						&totalLoad,
-						int(param.Scroll),
-						param.DelayScrollDuration,
-					)
-				}
-			}
-		},
-	)
-
-	err = chromedp.Run(ctx,
-		network.Enable(),
-		fetch.Enable().WithPatterns([]*fetch.RequestPattern{
-			{
-				URLPattern:   "*youtubei/v1/search*",
-				RequestStage: fetch.RequestStageResponse,
-			},
-		}),
-		chromedp.Navigate(uri.String()),
-
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var wg sync.WaitGroup
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				//slog.Info("listening should scroll")
-				for shouldScroll := range shouldScrollCh {
-					//slog.Info("onReceive shouldscroll", slog.Any("value", shouldScroll))
-					var nodes []*cdp.Node
-					err = chromedp.Nodes(`ytd-section-list-renderer div#contents ytd-item-section-renderer`, &nodes, chromedp.ByQueryAll).Do(ctx)
-					if err != nil {
-						listenErr <- fmt.Errorf("youtube crawler err : %w", err)
-						return
-					}
-
-					if !shouldScroll {
-						return
-					}
-
-					var res []byte
-
-					err := chromedp.Evaluate(`window.scrollTo(0,document.querySelector("ytd-section-list-renderer div#contents").scrollHeight);`, &res).Do(ctx)
-					//slog.Info("tryScrolling")
-					if err != nil {
-						listenErr <- fmt.Errorf("youtube crawler err : %w", err)
-						return
-					}
-
-					//slog.Info("scroll finish")
-				}
-			}()
-
-			err := chromedp.WaitVisible(`ytd-section-list-renderer div#contents ytd-item-section-renderer`, chromedp.ByQuery).Do(ctx)
-
-			if err != nil {
-				return err
-			}
-
-			results, err = crawler.collectFromSearchInitialData(ctx)
-			//slog.Info("collect initial data finish")
-
-			if err != nil {
-				return err
-			}
-			//slog.Info("get initial data success", slog.Any("resultLength", len(results)))
-
-			time.Sleep(param.DelayScrollDuration)
-			shouldScrollCh <- true
-			wg.Wait()
-
-			return nil
-		}),
-	)
-	close(listenErr)
-	close(resultChannel)
-	close(shouldScrollCh)
-
-	wg.Wait()
-
-	if err != nil {
-		return results, err
-	}
-
-	if err := <-listenErr; err != nil {
-		return results, err
-	}
-
-	return results, nil
+	paginator := newPaginator(yt.config, uri.String(), "*youtubei/v1/search*", param, decodeSearchContent)
+	paginator.ReadySelector = `ytd-section-list-renderer div#contents ytd-item-section-renderer`
+	paginator.ScrollScript = `window.scrollTo(0,document.querySelector("ytd-section-list-renderer div#contents").scrollHeight);`
+	paginator.InitialScript = `ytInitialData`
+	// first api page is also loaded by scroll
+	paginator.Scroll = param.Scroll + 1
+	paginator.DecodeInitial = decodeSearchInitialData
+	paginator.Stop = stopCondition(param, func(item VideoItem) string {
+		return item.ID
+	}, nil)
+
+	return paginator
 }
 
-// collect initial data from youtube page
-func (crawler *Youtube) collectFromSearchInitialData(ctx context.Context) ([]VideoItem, error) {
+// decode initial data of youtube page
+func decodeSearchInitialData(body []byte) ([]VideoItem, bool, error) {
 	var ytInitialData YtInitialDataResp
-	err := chromedp.Evaluate(`ytInitialData`, &ytInitialData).Do(ctx)
-
+	err := json.Unmarshal(body, &ytInitialData)
 	if err != nil {
-		return []VideoItem{}, err
+		return []VideoItem{}, false, err
 	}
 
-	return ytInitialData.GetVideoItems(), nil
+	return ytInitialData.GetVideoItems(), true, nil
 }
 
-func (crawler *Youtube) collectFromSearchContentAPI(
-	ctx context.Context,
-	ev *fetch.EventRequestPaused,
-	resultCh chan<- VideoItem,
-	errCh chan<- error,
-	shouldScrollCh chan<- bool,
-	totalLoad *int,
-	maxScroll int,
-	scrollDelayDuration time.Duration,
-) {
-	//slog.Info("collect from API", slog.Any("totalLoad", *totalLoad))
-	scroll := func() {
-		time.Sleep(scrollDelayDuration)
-
-		if *totalLoad >= maxScroll {
-			shouldScrollCh <- false
-		} else {
-			shouldScrollCh <- true
-		}
-
-		// regardless
-		*totalLoad = *totalLoad + 1
-	}
-
-	c := chromedp.FromContext(ctx)
-	e := cdp.WithExecutor(ctx, c.Target)
-	bByte, err := fetch.GetResponseBody(ev.RequestID).Do(e)
-	defer scroll()
-	// essential for trigger WaitVisible
-	if err != nil {
-		errCh <- fmt.Errorf("youtube crawler err : %w", err)
-		return
-	}
-	err = fetch.ContinueResponse(ev.RequestID).Do(e)
-	if err != nil {
-		errCh <- fmt.Errorf("youtube crawler err : %w", err)
-		return
-	}
+func decodeSearchContent(body []byte) ([]VideoItem, bool, error) {
+	var results []VideoItem
 	var searchResp SearchContentResp
-	err = json.Unmarshal(bByte, &searchResp)
+	err := json.Unmarshal(body, &searchResp)
 	if err != nil {
-		errCh <- fmt.Errorf("youtube crawler err : %w", err)
-		return
+		return results, false, err
 	}
 
-	// slog.Info("get response body succes", slog.Any("contentLength", len(bByte)))
-
-	// slog.Info("load commands", slog.Any("commandsLength", len(searchResp.OnResponseReceiveCommands)))
 	for _, command := range searchResp.OnResponseReceiveCommands {
-		//slog.Info("load continueItem", slog.Any("continueItemLength", len(command.AppendContinuationItemsAction.ContinuationItems)))
 		for _, continueItem := range command.AppendContinuationItemsAction.ContinuationItems {
-			//slog.Info("load videoItem", slog.Any("videoItemLength", len(continueItem.ItemSectionRenderer.GetVideoItems())))
-			for _, videoItem := range continueItem.ItemSectionRenderer.GetVideoItems() {
-				resultCh <- videoItem
-			}
-
+			results = append(results, continueItem.ItemSectionRenderer.GetVideoItems()...)
 		}
 	}
 
-	// slog.Info("finish collect from API")
+	return results, true, nil
 }
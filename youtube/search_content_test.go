@@ -0,0 +1,65 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDecodeSearchInitialData(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "search_content", "initial-0000.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, hasMore, err := decodeSearchInitialData(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	// channel result is skipped
+	if ids := videoIDs(items); !slices.Equal(ids, []string{"vid0000001a", "vid0000002b"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+
+	want := VideoItem{
+		ID:            "vid0000002b",
+		Channel:       Channel{Name: "doorcat", ID: "UCdoorcat", Endpoint: "/@doorcat"},
+		Thumbnails:    []Thumbnail{{Url: "https://i.ytimg.com/vi/vid0000002b/hqdefault.jpg", Width: 480, Height: 360}},
+		Duration:      3723,
+		DurationText:  "1:02:03",
+		ViewCount:     89000,
+		ViewCountText: "89,000 views",
+		Title:         "Cat learns to open doors",
+		Desc:          "Cat learns to open doors description",
+		PublishedTime: "3 weeks ago",
+	}
+	if !reflect.DeepEqual(items[1], want) {
+		t.Errorf("item = %+v, want %+v", items[1], want)
+	}
+}
+
+func TestDecodeSearchContent(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "search_content", "youtubei_v1_search-0000.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, hasMore, err := decodeSearchContent(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasMore {
+		t.Error("hasMore = false, want true")
+	}
+	if ids := videoIDs(items); !slices.Equal(ids, []string{"vid0000003c", "vid0000004d"}) {
+		t.Fatalf("ids = %v", ids)
+	}
+}
@@ -8,12 +8,26 @@ import "time"
 type SearchContentParam struct {
 	Term string
 
-	// Total scroll of page
-	// it will scroll as much of this
+	// Total api page loaded after the first one,
+	// page whose first items are embedded on the document is scrolled once more
 	Scroll uint
 
 	// Delay duration between scroll
 	DelayScrollDuration time.Duration
+
+	// Stop after this many items, zero means unlimited
+	MaxItems uint
+	// Stop at first item published before this time,
+	// GetUserContent only, the other lists are not ordered by time.
+	// Youtube only shows relative published time, e.g "3 days ago",
+	// so it is approximated
+	Until time.Time
+	// Stop at this item id, e.g last item seen on previous run.
+	// The item itself is not returned
+	StopAtID string
+	// Optional, stop when it returns true.
+	// Item type follows the called method, e.g VideoItem on SearchContent
+	Stop func(item any) bool
 }
 
 type VideoItem struct {
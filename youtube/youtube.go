@@ -5,6 +5,8 @@ package youtube
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/nandanurseptama/golang-crawler/crawler"
 )
@@ -14,6 +16,12 @@ type YoutubeCrawler interface {
 	SearchChannel(ctx context.Context, param SearchContentParam) ([]ChannelItem, error)
 	GetUserContent(ctx context.Context, param SearchContentParam) ([]UserContentItem, error)
 	GetContentComments(ctx context.Context, param SearchContentParam) ([]CommentItem, error)
+
+	// Stream variants yield every item as soon as its page is loaded
+	SearchContentStream(ctx context.Context, param SearchContentParam) iter.Seq2[VideoItem, error]
+	SearchChannelStream(ctx context.Context, param SearchContentParam) iter.Seq2[ChannelItem, error]
+	GetUserContentStream(ctx context.Context, param SearchContentParam) iter.Seq2[UserContentItem, error]
+	GetContentCommentsStream(ctx context.Context, param SearchContentParam) iter.Seq2[CommentItem, error]
 }
 
 type Youtube struct {
@@ -25,3 +33,47 @@ func NewCrawler(config crawler.Config) YoutubeCrawler {
 		config: config,
 	}
 }
+
+// newPaginator returns paginator with youtube scroll behaviour
+func newPaginator[T any](
+	config crawler.Config,
+	uri string,
+	pattern string,
+	param SearchContentParam,
+	decode func(body []byte) ([]T, bool, error),
+) *crawler.Paginator[T] {
+	return &crawler.Paginator[T]{
+		Config:   config,
+		Platform: "youtube",
+		URL:      uri,
+		Pattern:  pattern,
+		Decode:   decode,
+		Check:    checkPage,
+		Scroll:   param.Scroll,
+		Delay:    param.DelayScrollDuration,
+		MaxItems: param.MaxItems,
+	}
+}
+
+// stopCondition returns stop predicate of param.
+// publishedTime returns empty text when item has no published time
+func stopCondition[T any](
+	param SearchContentParam,
+	id func(item T) string,
+	publishedTime func(item T) string,
+) func(item T) bool {
+	now := time.Now()
+	return func(item T) bool {
+		if param.StopAtID != "" && id(item) == param.StopAtID {
+			return true
+		}
+
+		if !param.Until.IsZero() && publishedTime != nil {
+			if t, ok := parsePublishedTime(publishedTime(item), now); ok && t.Before(param.Until) {
+				return true
+			}
+		}
+
+		return param.Stop != nil && param.Stop(item)
+	}
+}
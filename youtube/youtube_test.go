@@ -0,0 +1,73 @@
+// Copyright The Golang Crawler Author
+// SPDX-License-Identifier: Apache-2.0
+
+package youtube
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nandanurseptama/golang-crawler/crawler"
+)
+
+// replayCrawler returns crawler replaying cassette of testdata/name,
+// the test is skipped when chrome of CHROME_PATH or PATH is not found
+func replayCrawler(t *testing.T, name string) *Youtube {
+	t.Helper()
+
+	path := os.Getenv("CHROME_PATH")
+	if path == "" {
+		for _, name := range []string{"google-chrome", "chromium", "chromium-browser", "headless-shell", "chrome"} {
+			if found, err := exec.LookPath(name); err == nil {
+				path = found
+				break
+			}
+		}
+	}
+	if path == "" {
+		t.Skip("chrome is not found, set CHROME_PATH to run replay tests")
+	}
+
+	cassette := crawler.NewCassette(filepath.Join("testdata", name), true)
+	server := cassette.Serve()
+	t.Cleanup(server.Close)
+
+	return &Youtube{config: crawler.Config{
+		ChromePath:         path,
+		Headless:           true,
+		DisableGPU:         true,
+		NoSandbox:          true,
+		DisableDevSHMUsage: true,
+		Cassette:           cassette,
+	}}
+}
+
+func TestStopCondition(t *testing.T) {
+	until := time.Now().AddDate(0, 0, -7)
+	id := func(item UserContentItem) string { return item.ID }
+	publishedTime := func(item UserContentItem) string { return item.PublishedTime }
+
+	tests := []struct {
+		name  string
+		param SearchContentParam
+		item  UserContentItem
+		want  bool
+	}{
+		{"no condition", SearchContentParam{}, UserContentItem{ID: "a", PublishedTime: "1 year ago"}, false},
+		{"stop at id", SearchContentParam{StopAtID: "a"}, UserContentItem{ID: "a"}, true},
+		{"older than until", SearchContentParam{Until: until}, UserContentItem{PublishedTime: "1 month ago"}, true},
+		{"newer than until", SearchContentParam{Until: until}, UserContentItem{PublishedTime: "3 days ago"}, false},
+		{"unknown published time", SearchContentParam{Until: until}, UserContentItem{PublishedTime: "Premieres soon"}, false},
+		{"stop func", SearchContentParam{Stop: func(item any) bool { return item.(UserContentItem).ID == "b" }}, UserContentItem{ID: "b"}, true},
+	}
+
+	for _, tt := range tests {
+		stop := stopCondition(tt.param, id, publishedTime)
+		if got := stop(tt.item); got != tt.want {
+			t.Errorf("%s : stop() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}